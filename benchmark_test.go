@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type benchAddress struct {
+	City string `validate:"required"`
+	Zip  string `validate:"len=5"`
+}
+
+// benchUser is a medium-sized struct which passes validation
+type benchUser struct {
+	ID      string   `validate:"required,uuid"`
+	Name    string   `validate:"required,len=4|len=6"`
+	Age     int      `validate:"gte=18,lte=120"`
+	Score   float64  `validate:"gt=0,ls=100"`
+	Level   uint8    `validate:"oneof=1 2 3"`
+	Role    string   `validate:"oneof=admin user,!eq=root"`
+	Parent  *int     `validate:"required,gt=0"`
+	Tags    []string `validate:"len=2"`
+	Note    string   `validate:"omitempty,len=3"`
+	Active  bool     `validate:"required"`
+	Address benchAddress
+	Backup  *benchAddress
+	Meta    struct {
+		Version int `validate:"gt=0"`
+	}
+	secret int `validate:"ls=10"`
+}
+
+func newBenchUser() *benchUser {
+	parent := 1
+	u := &benchUser{
+		ID:      "123e4567-e89b-12d3-a456-426614174000",
+		Name:    "john",
+		Age:     30,
+		Score:   50,
+		Level:   2,
+		Role:    "admin",
+		Parent:  &parent,
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Address: benchAddress{City: "taipei", Zip: "10001"},
+		Backup:  &benchAddress{City: "tainan", Zip: "70001"},
+		secret:  3,
+	}
+	u.Meta.Version = 1
+	return u
+}
+
+func BenchmarkValidateStruct(b *testing.B) {
+	v := New()
+	u := newBenchUser()
+	if err := v.ValidateStruct(u); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("pointer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = v.ValidateStruct(u)
+		}
+	})
+	b.Run("value", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = v.ValidateStruct(*u)
+		}
+	})
+}
+
+func TestValidateStructNoAlloc(t *testing.T) {
+	v := New()
+	u := newBenchUser()
+	assert.NoError(t, v.ValidateStruct(u))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = v.ValidateStruct(u)
+	})
+	assert.Zero(t, allocs)
+}
+
+// BenchmarkRule measure checking a single rule against field of each kind
+func BenchmarkRule(b *testing.B) {
+	v := New()
+	cases := []struct {
+		name  string
+		tag   string
+		value interface{}
+	}{
+		{"gt/int", "gt=3", 5},
+		{"gt/int8", "gt=3", int8(5)},
+		{"lte/uint", "lte=10", uint(5)},
+		{"gte/float", "gte=1.5", 2.5},
+		{"eq/string", "eq=admin", "admin"},
+		{"oneof/int", "oneof=1 2 3 4 5", 5},
+		{"oneof/string", "oneof=a b c d e", "e"},
+		{"len/slice", "len=2", []int{1, 2}},
+		{"required/string", "required", "a"},
+		{"gt/nil", "gt=3", (*int)(nil)},
+	}
+
+	for _, c := range cases {
+		value := reflect.ValueOf(c.value)
+		fieldType, isPtr := derefType(value.Type())
+		fs, err := v.parseTag(fieldType, c.tag, isPtr)
+		if err != nil {
+			b.Fatal(err)
+		}
+		vf := fs[0]
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				vf.CheckPass(value)
+			}
+		})
+	}
+}
+
+func BenchmarkValidateSlice(b *testing.B) {
+	users := make([]*benchUser, 10000)
+	for i := range users {
+		users[i] = newBenchUser()
+	}
+	ctx := context.Background()
+
+	for _, workers := range []int{1, 4, 8} {
+		v := New(WithWorkers(workers))
+		if err := v.ValidateSlice(ctx, users); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("workers=%v", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = v.ValidateSlice(ctx, users)
+			}
+		})
+	}
+}
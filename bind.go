@@ -0,0 +1,244 @@
+package validator
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Bind populate s, which must be a pointer to struct, from values by the key in
+// tagName tag, and validate it. the key defaults to field name, "-" skips the
+// field, and nested struct fields are keyed by dotted path, e.g. address.city,
+// while fields of embedded struct are keyed as promoted fields.
+// slice fields take all values of the key, others take the first one, and an
+// empty value leaves non-string fields zero. time.Duration is parsed by
+// time.ParseDuration. values that cannot be converted are reported as
+// ValidateError with rule type=<field type>, before errors of the rules.
+// presence of keys is tracked like ValidateJSON, and failed fields are reported
+// by key.
+func (v *Validator) Bind(s interface{}, values url.Values, tagName string) error {
+	return v.BindLookup(s, tagName, ".", func(key string, _ reflect.StructField) ([]string, bool) {
+		vals, ok := values[key]
+		return vals, ok && len(vals) > 0
+	})
+}
+
+// BindQuery bind s from query parameters by `query` tag
+func (v *Validator) BindQuery(s interface{}, values url.Values) error {
+	return v.Bind(s, values, "query")
+}
+
+// BindForm bind s from form values by `form` tag
+func (v *Validator) BindForm(s interface{}, values url.Values) error {
+	return v.Bind(s, values, "form")
+}
+
+// BindLookup is like Bind, but finds values of field by lookup, and joins keys
+// of nested fields by sep. It allows binding from other sources, e.g. environment
+// variables.
+func (v *Validator) BindLookup(s interface{}, tagName, sep string, lookup func(key string, field reflect.StructField) ([]string, bool)) error {
+	value := reflect.ValueOf(s)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrorValidateWrongType("pointer to struct")
+	}
+
+	b := &binder{
+		v:       v,
+		tagName: tagName,
+		sep:     sep,
+		lookup:  lookup,
+		present: make(map[string]bool),
+	}
+	b.bindStruct(value.Elem(), "", "")
+
+	t := &traversal{present: b.present, naming: naming{tag: tagName, sep: sep}}
+	if len(b.failed) > 0 {
+		t.filter = newFieldFilter(false, b.failed)
+	}
+	err := v.validateStruct(s, "", t)
+	if len(b.errors) == 0 {
+		return err
+	}
+	if err == nil {
+		return b.errors
+	}
+	if errs, ok := err.(ValidateErrors); ok {
+		return append(b.errors, errs...)
+	}
+	return err
+}
+
+type binder struct {
+	v       *Validator
+	tagName string
+	sep     string
+	lookup  func(key string, field reflect.StructField) ([]string, bool)
+
+	// present holds dotted paths of bound fields and whether their key is found
+	present map[string]bool
+	// failed holds dotted paths of fields failed to convert
+	failed []string
+	errors ValidateErrors
+}
+
+// key return the key of field, and false if field is not bound. like
+// encoding/json, embedded struct is bound even if it's unexported, since its
+// exported fields are promoted, but not pointer to it which cannot be set.
+func (b *binder) key(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get(b.tagName), ",")
+	if name == "-" {
+		return "", false
+	}
+	if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func (b *binder) join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + b.sep + key
+}
+
+// bindStruct bind fields of value, and report whether any key is found
+func (b *binder) bindStruct(value reflect.Value, prefix, path string) bool {
+	found := false
+	vType := value.Type()
+	for i := 0; i < vType.NumField(); i++ {
+		fieldType := vType.Field(i)
+		key, ok := b.key(fieldType)
+		if !ok {
+			continue
+		}
+		key = b.join(prefix, key)
+		fieldPath := joinKey(path, fieldType.Name)
+		field := value.Field(i)
+
+		if base, _ := derefType(fieldType.Type); base.Kind() == reflect.Struct && !isTextUnmarshaler(base) {
+			target := reflect.New(base)
+			if base == fieldType.Type {
+				target = field.Addr()
+			}
+			// fields of embedded struct are keyed as promoted fields
+			nestedPrefix, nestedPath := key, fieldPath
+			if b.v.flatten(&fieldType, b.tagName) {
+				nestedPrefix, nestedPath = prefix, path
+			}
+			ok = b.bindStruct(target.Elem(), nestedPrefix, nestedPath)
+			if ok && base != fieldType.Type {
+				setPointer(field, target)
+			}
+		} else {
+			var vals []string
+			vals, ok = b.lookup(key, fieldType)
+			if ok {
+				if err := setValues(field, vals); err != nil {
+					b.failed = append(b.failed, fieldPath)
+					b.errors = append(b.errors, ErrorValidateConversion(key, fieldType.Type.String()))
+				}
+			}
+		}
+		b.present[fieldPath] = ok
+		found = found || ok
+	}
+	return found
+}
+
+// setPointer set field, which is pointer of any level, to ptr
+func setPointer(field, ptr reflect.Value) {
+	for ptr.Type() != field.Type() {
+		p := reflect.New(ptr.Type())
+		p.Elem().Set(ptr)
+		ptr = p
+	}
+	field.Set(ptr)
+}
+
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// setValues set field to all of vals if it's slice, or to the first one
+func setValues(field reflect.Value, vals []string) error {
+	if field.Kind() == reflect.Slice && !isTextUnmarshaler(field.Type()) {
+		slice := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+		for i, str := range vals {
+			if err := setString(slice.Index(i), str); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setString(field, vals[0])
+}
+
+// setString convert str into the type of field and set it
+func setString(field reflect.Value, str string) error {
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setString(ptr.Elem(), str); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(str))
+	}
+
+	kind := field.Kind()
+	if kind == reflect.String {
+		field.SetString(str)
+		return nil
+	}
+	if str == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	if kind == reflect.Bool {
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+		return nil
+	}
+
+	n, err := parseStringToType(kind, str)
+	if err != nil {
+		return err
+	}
+	switch {
+	case isInt(kind) && !field.OverflowInt(n.(int64)):
+		field.SetInt(n.(int64))
+	case isUint(kind) && !field.OverflowUint(n.(uint64)):
+		field.SetUint(n.(uint64))
+	case isFloat(kind) && !field.OverflowFloat(n.(float64)):
+		field.SetFloat(n.(float64))
+	case isComplex(kind) && !field.OverflowComplex(n.(complex128)):
+		field.SetComplex(n.(complex128))
+	default:
+		return fmt.Errorf("%v overflows %v", str, field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,764 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guan-wei-huang/validator/internal/tagexpr"
+)
+
+const (
+	tagName      = "validate"
+	validatorPkg = "github.com/guan-wei-huang/validator"
+)
+
+var builtinKinds = map[string]reflect.Kind{
+	"bool":       reflect.Bool,
+	"int":        reflect.Int,
+	"int8":       reflect.Int8,
+	"int16":      reflect.Int16,
+	"int32":      reflect.Int32,
+	"rune":       reflect.Int32,
+	"int64":      reflect.Int64,
+	"uint":       reflect.Uint,
+	"uint8":      reflect.Uint8,
+	"byte":       reflect.Uint8,
+	"uint16":     reflect.Uint16,
+	"uint32":     reflect.Uint32,
+	"uint64":     reflect.Uint64,
+	"uintptr":    reflect.Uintptr,
+	"float32":    reflect.Float32,
+	"float64":    reflect.Float64,
+	"complex64":  reflect.Complex64,
+	"complex128": reflect.Complex128,
+	"string":     reflect.String,
+	"any":        reflect.Interface,
+	"error":      reflect.Interface,
+}
+
+// typeInfo is the resolved type of field
+type typeInfo struct {
+	// ptrs is the number of pointers before the underlying type
+	ptrs int
+	kind reflect.Kind
+	// local is the name of struct type declared in package
+	local string
+	// external is set for types from other packages which cannot be resolved
+	external bool
+	// elem is the element type of array and slice
+	elem *typeInfo
+}
+
+type generator struct {
+	fset  *token.FileSet
+	pkg   string
+	types map[string]*ast.TypeSpec
+
+	buf     bytes.Buffer
+	imports map[string]bool
+	// names of package level variables holding compiled patterns, in order
+	regexes  map[string]string
+	patterns []string
+	email    bool
+	// dynamic is set if any interface field is validated
+	dynamic bool
+
+	// structs whose methods are generated or queued
+	seen  map[string]bool
+	queue []string
+}
+
+// generate parse package in dir and return source of Validate methods of
+// types, or all structs in package if types is empty. output is skipped when
+// parsing.
+func generate(dir string, types []string, output string) ([]byte, error) {
+	g := &generator{
+		fset:    token.NewFileSet(),
+		types:   make(map[string]*ast.TypeSpec),
+		imports: make(map[string]bool),
+		regexes: make(map[string]string),
+		seen:    make(map[string]bool),
+	}
+	if err := g.parseDir(dir, output); err != nil {
+		return nil, err
+	}
+
+	if len(types) == 0 {
+		for name, spec := range g.types {
+			if _, ok := spec.Type.(*ast.StructType); ok && spec.TypeParams == nil {
+				types = append(types, name)
+			}
+		}
+		sort.Strings(types)
+	}
+	for _, name := range types {
+		spec, ok := g.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %v not found in %v", name, dir)
+		}
+		if _, ok := spec.Type.(*ast.StructType); !ok {
+			return nil, fmt.Errorf("type %v is not a struct", name)
+		}
+		g.enqueue(name)
+	}
+
+	for len(g.queue) > 0 {
+		name := g.queue[0]
+		g.queue = g.queue[1:]
+		if err := g.genStruct(name); err != nil {
+			return nil, err
+		}
+	}
+	return g.source()
+}
+
+// parseDir parse non-test files in dir which match build constraints of
+// current platform
+func (g *generator) parseDir(dir, output string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		file, err := parser.ParseFile(g.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		if g.pkg == "" {
+			g.pkg = file.Name.Name
+		} else if g.pkg != file.Name.Name {
+			return fmt.Errorf("multiple packages in %v: %v, %v", dir, g.pkg, file.Name.Name)
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				g.types[spec.Name.Name] = spec
+			}
+		}
+	}
+	if g.pkg == "" {
+		return fmt.Errorf("no Go package found in %v", dir)
+	}
+	return nil
+}
+
+func (g *generator) enqueue(name string) {
+	if !g.seen[name] {
+		g.seen[name] = true
+		g.queue = append(g.queue, name)
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// resolve find the kind of type expression
+func (g *generator) resolve(expr ast.Expr) (typeInfo, error) {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		t, err := g.resolve(e.X)
+		t.ptrs++
+		return t, err
+
+	case *ast.ParenExpr:
+		return g.resolve(e.X)
+
+	case *ast.Ident:
+		if spec, ok := g.types[e.Name]; ok {
+			if spec.TypeParams != nil {
+				return typeInfo{}, fmt.Errorf("generic type %v is not supported", e.Name)
+			}
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				return typeInfo{kind: reflect.Struct, local: e.Name}, nil
+			}
+			return g.resolve(spec.Type)
+		}
+		if kind, ok := builtinKinds[e.Name]; ok {
+			return typeInfo{kind: kind}, nil
+		}
+		return typeInfo{}, fmt.Errorf("unknown type %v", e.Name)
+
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "time" && e.Sel.Name == "Duration" {
+			return typeInfo{kind: reflect.Int64}, nil
+		}
+		return typeInfo{external: true}, nil
+
+	case *ast.ArrayType:
+		elem, err := g.resolve(e.Elt)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		t := typeInfo{kind: reflect.Array, elem: &elem}
+		if e.Len == nil {
+			t.kind = reflect.Slice
+		}
+		return t, nil
+
+	case *ast.MapType:
+		return typeInfo{kind: reflect.Map}, nil
+	case *ast.ChanType:
+		return typeInfo{kind: reflect.Chan}, nil
+	case *ast.FuncType:
+		return typeInfo{kind: reflect.Func}, nil
+	case *ast.InterfaceType:
+		return typeInfo{kind: reflect.Interface}, nil
+	case *ast.StructType:
+		return typeInfo{}, fmt.Errorf("anonymous struct is not supported")
+	}
+	return typeInfo{}, fmt.Errorf("unsupported type %T", expr)
+}
+
+// fieldNames return names of field, the type name for embedded field
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return []string{e.Name}
+	case *ast.SelectorExpr:
+		return []string{e.Sel.Name}
+	}
+	return nil
+}
+
+func (g *generator) genStruct(name string) error {
+	st := g.types[name].Type.(*ast.StructType)
+
+	g.printf("\n// Validate validate s by its validate tags, the same as Validator.ValidateStruct\n")
+	g.printf("func (s *%v) Validate() error {\n", name)
+	g.printf("d := validator.NewDynamic(s)\nerrs := s.validateFields(%q, d)\n", name)
+	g.printf("if err := d.Err(); err != nil {\nreturn err\n}\n")
+	g.printf("if len(errs) > 0 {\nreturn errs\n}\nreturn nil\n}\n")
+
+	g.printf("\nfunc (s *%v) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {\n", name)
+	// interface field may hold nil pointer
+	g.printf("if s == nil {\nreturn nil\n}\n")
+	g.printf("var errs validator.ValidateErrors\n")
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return err
+			}
+			tag = reflect.StructTag(raw).Get(tagName)
+		}
+
+		embedded := len(field.Names) == 0
+		for _, fieldName := range fieldNames(field) {
+			if fieldName == "_" {
+				continue
+			}
+			if err := g.genField(fieldName, field.Type, tag, embedded); err != nil {
+				return fmt.Errorf("%v: %v.%v: %w", g.fset.Position(field.Pos()), name, fieldName, err)
+			}
+		}
+	}
+	g.printf("return errs\n}\n")
+	return nil
+}
+
+// genField generate checks of field. fields of embedded struct are named as
+// promoted fields.
+func (g *generator) genField(name string, typ ast.Expr, tag string, embedded bool) error {
+	t, err := g.resolve(typ)
+	if err != nil {
+		return err
+	}
+	if t.external {
+		if tag != "" {
+			return fmt.Errorf("rules on type from other package are not supported")
+		}
+		return nil
+	}
+
+	var segments []*tagexpr.Node
+	if tag != "" {
+		if segments, err = tagexpr.Parse(tag); err != nil {
+			return err
+		}
+	}
+	// top level groups are flattened like the validator does
+	var checks []*tagexpr.Node
+	var flatten func(n *tagexpr.Node)
+	flatten = func(n *tagexpr.Node) {
+		if n.Kind == tagexpr.And && !n.Negate {
+			for _, c := range n.Children {
+				flatten(c)
+			}
+			return
+		}
+		checks = append(checks, n)
+	}
+	for _, n := range segments {
+		flatten(n)
+	}
+
+	nested := t.kind == reflect.Struct && t.local != ""
+	// dynamic value of interface is validated by its generated methods, or by
+	// reflection if it has none
+	dynamic := t.kind == reflect.Interface
+	if len(checks) == 0 && !nested && !dynamic {
+		return nil
+	}
+
+	f := &fieldGen{g: g, t: t, expr: "s." + name}
+	omitEmpty := false
+	exprs := make([]string, len(checks))
+	for i, n := range checks {
+		if n.Kind == tagexpr.Rule && n.Name == "omitempty" {
+			omitEmpty = true
+		}
+		if exprs[i], err = f.build(n); err != nil {
+			return fmt.Errorf("segment %v(%v): %w", i, n.Raw, err)
+		}
+	}
+
+	// rules which always pass are dropped
+	var failing []int
+	for i, expr := range exprs {
+		if expr != "true" {
+			failing = append(failing, i)
+		}
+	}
+	if len(failing) == 0 && !nested && !dynamic {
+		return nil
+	}
+
+	g.printf("// %v\n", name)
+	if omitEmpty {
+		g.printf("if %v {\n", f.zero(f.expr, t.ptrs > 0, false))
+	} else {
+		g.printf("{\n")
+	}
+	for _, i := range failing {
+		n := checks[i]
+		g.printf("if %v {\n", not(exprs[i]))
+		g.printf("errs = append(errs, validator.ErrorValidateFalse(levelName+%q, %q))\n}\n", "."+name, n.Raw)
+	}
+	if nested {
+		g.enqueue(t.local)
+		value := f.value()
+		if t.ptrs > 0 {
+			value = "(" + value + ")"
+		}
+		levelName := fmt.Sprintf("levelName+%q", "."+name)
+		if embedded {
+			levelName = "levelName"
+		}
+		call := fmt.Sprintf("errs = append(errs, %v.validateFields(%v, d)...)\n", value, levelName)
+		if guard := f.guard(); guard != "" {
+			g.printf("if %v {\n%v}\n", guard, call)
+		} else {
+			g.printf("%v", call)
+		}
+	}
+	if dynamic {
+		g.dynamic = true
+		call := fmt.Sprintf("errs = append(errs, validatorgenDynamic(levelName+%q, %v, d)...)\n", "."+name, f.value())
+		if guard := f.guard(); guard != "" {
+			g.printf("if %v {\n%v}\n", guard, call)
+		} else {
+			g.printf("%v", call)
+		}
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// fieldGen build expressions of rules on a field
+type fieldGen struct {
+	g    *generator
+	t    typeInfo
+	expr string
+}
+
+// guard return condition that all pointers of field are not nil
+func (f *fieldGen) guard() string {
+	conds := make([]string, f.t.ptrs)
+	for i := range conds {
+		conds[i] = strings.Repeat("*", i) + f.expr + " != nil"
+	}
+	return strings.Join(conds, " && ")
+}
+
+// value return expression of the dereferenced field
+func (f *fieldGen) value() string {
+	return strings.Repeat("*", f.t.ptrs) + f.expr
+}
+
+// zero return condition that expr is zero value, or not if zero is false, the
+// same as reflect.Value.IsZero
+func (f *fieldGen) zero(expr string, isPtr, zero bool) string {
+	op, join, not := "==", " && ", ""
+	if !zero {
+		op, join, not = "!=", " || ", "!"
+	}
+	kind := f.t.kind
+	switch {
+	case isPtr:
+		return fmt.Sprintf("%v %v nil", expr, op)
+	case kind == reflect.Bool:
+		if zero {
+			return "!" + expr
+		}
+		return expr
+	case isInt(kind), isUint(kind), kind == reflect.Uintptr:
+		return fmt.Sprintf("%v %v 0", expr, op)
+	case isFloat(kind):
+		f.g.imports["math"] = true
+		return fmt.Sprintf("math.Float64bits(float64(%v)) %v 0", expr, op)
+	case isComplex(kind):
+		f.g.imports["math"] = true
+		return fmt.Sprintf("math.Float64bits(real(complex128(%[1]v))) %[2]v 0%[3]vmath.Float64bits(imag(complex128(%[1]v))) %[2]v 0", expr, op, join)
+	case kind == reflect.String:
+		return fmt.Sprintf(`%v %v ""`, expr, op)
+	case kind == reflect.Struct || kind == reflect.Array:
+		f.g.imports["reflect"] = true
+		return fmt.Sprintf("%vreflect.ValueOf(%v).IsZero()", not, expr)
+	}
+	return fmt.Sprintf("%v %v nil", expr, op)
+}
+
+// not return negation of expr, removing double negation
+func not(expr string) string {
+	if strings.HasPrefix(expr, "!(") && closingParen(expr, 1) == len(expr)-1 {
+		return expr[2 : len(expr)-1]
+	}
+	if strings.HasPrefix(expr, "(") && closingParen(expr, 0) == len(expr)-1 {
+		return "!" + expr
+	}
+	return "!(" + expr + ")"
+}
+
+// closingParen return index of the parenthesis closing the one at open,
+// skipping string literals
+func closingParen(expr string, open int) int {
+	depth := 0
+	for i := open; i < len(expr); i++ {
+		switch expr[i] {
+		case '"':
+			for i++; i < len(expr) && expr[i] != '"'; i++ {
+				if expr[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// build return expression which is true if n passes
+func (f *fieldGen) build(n *tagexpr.Node) (string, error) {
+	var expr string
+	switch n.Kind {
+	case tagexpr.Rule:
+		e, err := f.rule(n)
+		if err != nil {
+			return "", err
+		}
+		expr = e
+
+	default:
+		sep := " && "
+		if n.Kind == tagexpr.Or {
+			sep = " || "
+		}
+		exprs := make([]string, len(n.Children))
+		for i, c := range n.Children {
+			e, err := f.build(c)
+			if err != nil {
+				return "", err
+			}
+			exprs[i] = e
+		}
+		expr = "(" + strings.Join(exprs, sep) + ")"
+	}
+
+	if n.Negate {
+		return not(expr), nil
+	}
+	return expr, nil
+}
+
+// rule return expression of a single rule. rules other than required fail on
+// nil pointer.
+func (f *fieldGen) rule(n *tagexpr.Node) (string, error) {
+	if n.Name == "required" && f.t.ptrs > 0 {
+		return f.guard(), nil
+	}
+	expr, err := f.check(n)
+	if err != nil || expr == "true" || expr == "false" || f.t.ptrs == 0 {
+		return expr, err
+	}
+	return "(" + f.guard() + " && " + expr + ")", nil
+}
+
+var compareOps = map[string]string{"gt": ">", "ls": "<", "gte": ">=", "lte": "<=", "eq": "=="}
+
+func (f *fieldGen) check(n *tagexpr.Node) (string, error) {
+	kind, value, param := f.t.kind, f.value(), n.Param
+	switch n.Name {
+	case "gt", "eq", "ls", "gte", "lte":
+		if n.Name == "eq" && kind == reflect.String {
+			return fmt.Sprintf("string(%v) == %q", value, param), nil
+		}
+		lit, err := f.literal(kind, param)
+		if err != nil {
+			return "", err
+		}
+		if isComplex(kind) {
+			switch n.Name {
+			case "gt", "ls":
+				return "false", nil
+			}
+			return fmt.Sprintf("complex128(%v) == %v", value, lit), nil
+		}
+		return fmt.Sprintf("%v(%v) %v %v", widen(kind), value, compareOps[n.Name], lit), nil
+
+	case "min", "max":
+		if kind != reflect.Array && kind != reflect.Slice {
+			return "", unsupportedRule(n.Raw)
+		}
+		if _, err := f.literal(f.t.elem.kind, param); err != nil {
+			return "", err
+		}
+		return "true", nil
+
+	case "len":
+		if _, err := f.literal(reflect.Int, param); err != nil {
+			return "", err
+		}
+		if kind != reflect.String && kind != reflect.Array && kind != reflect.Slice && kind != reflect.Map {
+			return "", unsupportedRule(n.Raw)
+		}
+		size, _ := strconv.ParseInt(param, 10, 64)
+		return fmt.Sprintf("len(%v) == %v", value, size), nil
+
+	case "required":
+		return f.zero(value, false, false), nil
+
+	case "omitempty":
+		return "true", nil
+
+	case "oneof":
+		var exprs []string
+		for _, s := range n.Fields {
+			if kind == reflect.String {
+				exprs = append(exprs, fmt.Sprintf("string(%v) == %q", value, s))
+				continue
+			}
+			lit, err := f.literal(kind, s)
+			if err != nil {
+				return "", err
+			}
+			exprs = append(exprs, fmt.Sprintf("%v(%v) == %v", widen(kind), value, lit))
+		}
+		if len(exprs) == 0 {
+			return "false", nil
+		}
+		return "(" + strings.Join(exprs, " || ") + ")", nil
+
+	case "email", "uuid", "regex":
+		if kind != reflect.String {
+			return "", unsupportedRule(n.Raw)
+		}
+		switch n.Name {
+		case "email":
+			f.g.email = true
+			f.g.imports["net/mail"] = true
+			return fmt.Sprintf("validatorgenIsEmail(string(%v))", value), nil
+		case "uuid":
+			param = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+		}
+		if _, err := regexp.Compile(param); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%v.MatchString(string(%v))", f.g.regex(param), value), nil
+	}
+	return "", unsupportedRule(n.Raw)
+}
+
+func unsupportedRule(rule string) error {
+	return fmt.Errorf("got unsupported tag: %v", rule)
+}
+
+// literal parse param into Go literal of the type that kind is widened to, the
+// same as the validator parses it
+func (f *fieldGen) literal(kind reflect.Kind, param string) (string, error) {
+	switch {
+	case isInt(kind):
+		n, err := strconv.ParseInt(param, 10, 64)
+		return strconv.FormatInt(n, 10), err
+	case isUint(kind):
+		n, err := strconv.ParseUint(param, 10, 64)
+		return strconv.FormatUint(n, 10), err
+	case isFloat(kind):
+		n, err := strconv.ParseFloat(param, 64)
+		return f.floatLiteral(n), err
+	case isComplex(kind):
+		n, err := strconv.ParseComplex(param, 128)
+		return fmt.Sprintf("complex(%v, %v)", f.floatLiteral(real(n)), f.floatLiteral(imag(n))), err
+	}
+	return "", fmt.Errorf("cannot parse %v to type %v", param, kind)
+}
+
+func (f *fieldGen) floatLiteral(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		f.g.imports["math"] = true
+		return "math.NaN()"
+	case math.IsInf(n, 1):
+		f.g.imports["math"] = true
+		return "math.Inf(1)"
+	case math.IsInf(n, -1):
+		f.g.imports["math"] = true
+		return "math.Inf(-1)"
+	}
+	return strconv.FormatFloat(n, 'g', -1, 64)
+}
+
+// widen return the type which values of kind are converted to for comparison
+func widen(kind reflect.Kind) string {
+	switch {
+	case isInt(kind):
+		return "int64"
+	case isUint(kind):
+		return "uint64"
+	case isFloat(kind):
+		return "float64"
+	}
+	return "complex128"
+}
+
+// regex return name of package level variable holding compiled pattern
+func (g *generator) regex(pattern string) string {
+	name, ok := g.regexes[pattern]
+	if !ok {
+		name = fmt.Sprintf("validatorgenRegex%v", len(g.regexes))
+		g.regexes[pattern] = name
+		g.patterns = append(g.patterns, pattern)
+		g.imports["regexp"] = true
+	}
+	return name
+}
+
+func (g *generator) source() ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by validatorgen. DO NOT EDIT.\n\npackage %v\n\n", g.pkg)
+
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	out.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&out, "%q\n", path)
+	}
+	fmt.Fprintf(&out, "\n%q\n)\n", validatorPkg)
+
+	if len(g.patterns) > 0 {
+		out.WriteString("\nvar (\n")
+		for _, pattern := range g.patterns {
+			fmt.Fprintf(&out, "%v = regexp.MustCompile(%q)\n", g.regexes[pattern], pattern)
+		}
+		out.WriteString(")\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	if g.dynamic {
+		g.genDynamic(&out)
+	}
+	if g.email {
+		out.WriteString(`
+func validatorgenIsEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+`)
+	}
+	return format.Source(out.Bytes())
+}
+
+// genDynamic generate dispatch of value held by interface field to generated
+// methods, which accepts both struct and pointer to it like the validator.
+// pointer already on the path is skipped, so that cyclic values terminate.
+func (g *generator) genDynamic(out *bytes.Buffer) {
+	names := make([]string, 0, len(g.seen))
+	for name := range g.seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out.WriteString(`
+func validatorgenDynamic(levelName string, value interface{}, d *validator.Dynamic) validator.ValidateErrors {
+	switch v := value.(type) {
+`)
+	for _, name := range names {
+		fmt.Fprintf(out, "case *%v:\nif !d.Enter(v) {\nreturn nil\n}\ndefer d.Leave(v)\nreturn v.validateFields(levelName, d)\n", name)
+		fmt.Fprintf(out, "case %v:\nreturn v.validateFields(levelName, d)\n", name)
+	}
+	out.WriteString("}\nreturn d.Validate(levelName, value)\n}\n")
+}
+
+func isInt(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUint(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isComplex(kind reflect.Kind) bool {
+	return kind == reflect.Complex64 || kind == reflect.Complex128
+}
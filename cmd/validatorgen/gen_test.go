@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateUpToDate check that generated code of parity test is up to date
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "gentest")
+	src, err := generate(dir, []string{"User"}, "validator_gen.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+	expect, err := os.ReadFile(filepath.Join(dir, "validator_gen.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expect), string(src), "run go generate ./internal/gentest")
+}
+
+func TestGenerateError(t *testing.T) {
+	cases := []struct {
+		src string
+		err string
+	}{
+		{"type T struct { A int `validate:\"gt=x\"` }", "T.A: segment 0(gt=x): "},
+		{"type T struct { A string `validate:\"gt=1\"` }", "T.A: segment 0(gt=1): cannot parse 1 to type string"},
+		{"type T struct { A int `validate:\"email\"` }", "T.A: segment 0(email): got unsupported tag: email"},
+		{"type T struct { A int `validate:\"len=1\"` }", "T.A: segment 0(len=1): got unsupported tag: len=1"},
+		{"type T struct { A string `validate:\"myalias\"` }", "T.A: segment 0(myalias): got unsupported tag: myalias"},
+		{"type T struct { A string `validate:\"len=1,(\"` }", "T.A: segment 1((): syntax error at offset 7: unexpected end of tag"},
+		{"type T struct { A struct{ B int } }", "T.A: anonymous struct is not supported"},
+		{"import \"net/url\"\ntype T struct { A url.URL `validate:\"required\"` }", "T.A: rules on type from other package are not supported"},
+		{"type T int", "type T is not a struct"},
+	}
+
+	for _, c := range cases {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package p\n"+c.src+"\n"), 0o644)
+		assert.NoError(t, err)
+		_, err = generate(dir, []string{"T"}, "validator_gen.go")
+		if assert.Error(t, err, c.src) {
+			assert.Contains(t, err.Error(), c.err, c.src)
+		}
+	}
+}
+
+func TestGenerateBuildConstraint(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"t.go":      "package p\ntype T struct { A int `validate:\"gt=0\"` }\n",
+		"tools.go":  "//go:build ignore\n\npackage main\ntype U struct{}\n",
+		"t_test.go": "package p_test\n",
+	}
+	for name, src := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+	}
+	src, err := generate(dir, nil, "validator_gen.go")
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(src), "package p\n")
+		assert.NotContains(t, string(src), "*U)")
+	}
+}
@@ -0,0 +1,63 @@
+// Command validatorgen generates Validate methods which check the validate tags
+// of structs without reflection, with the same semantics and errors as
+// Validator.ValidateStruct. It's meant to be run by go generate:
+//
+//	//go:generate go run github.com/guan-wei-huang/validator/cmd/validatorgen -type=User,Address
+//
+// For each struct, it generates
+//
+//	func (s *T) Validate() error
+//
+// which returns nil or validator.ValidateErrors. Struct types of fields declared
+// in the same package are validated as nested structs, and get their methods
+// generated as well.
+//
+// Aliases, validation groups and map rules are registered at runtime, so they
+// are not supported. Struct types from other packages, e.g. time.Time, are not
+// traversed, and fields of such types cannot have rules. Interface fields are
+// traversed only if they hold a pointer to a struct with generated methods in
+// the same package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validatorgen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct types; all structs of package if empty")
+	output := flag.String("output", "", "output file name; default <dir>/validator_gen.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: validatorgen [-type T,...] [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, "validator_gen.go")
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+	src, err := generate(dir, types, filepath.Base(out))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Compile register rules of types in advance, so that the first validation of
+// them doesn't parse tags. types are values or nil pointers of structs, e.g.
+// User{} or (*User)(nil), or their reflect.Type. tag errors of all types are
+// returned together as TagErrors.
+func (v *Validator) Compile(types ...interface{}) error {
+	var errs TagErrors
+	for _, t := range types {
+		sType, err := structType(t)
+		if err == nil {
+			_, err = v.loadStructRule(sType, "")
+		}
+		switch err := err.(type) {
+		case nil:
+		case TagErrors:
+			errs = append(errs, err...)
+		default:
+			typ, ok := t.(reflect.Type)
+			if !ok {
+				typ = reflect.TypeOf(t)
+			}
+			errs = append(errs, &TagError{Struct: fmt.Sprint(typ), Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Rules return description of rules of struct s, which is given as Compile, and
+// register them if they are not yet. it's a copy, so changing it doesn't affect
+// validation.
+func (v *Validator) Rules(s interface{}) (*StructRules, error) {
+	sType, err := structType(s)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := v.loadStructRule(sType, "")
+	if err != nil {
+		return nil, err
+	}
+	return v.describeStruct(rule), nil
+}
+
+// structType return the struct type of s, which is a struct, pointer to struct
+// or reflect.Type of them
+func structType(s interface{}) (reflect.Type, error) {
+	t, ok := s.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(s)
+	}
+	if t != nil {
+		t, _ = derefType(t)
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrorValidateWrongType(reflect.Struct.String())
+	}
+	return t, nil
+}
+
+// StructRules describes rules of a struct
+type StructRules struct {
+	// Name is the key of rules in cache, which is the type name, or the path
+	// of field for anonymous struct
+	Name   string
+	Type   reflect.Type
+	Fields []FieldRules
+}
+
+// FieldRules describes rules of a field in the order they are checked
+type FieldRules struct {
+	Name  string
+	Type  reflect.Type
+	Rules []Rule
+	// Nested holds rules of field whose type is struct or pointer to struct
+	Nested *StructRules
+}
+
+// Rule describes a rule of field. rules combined by `|` or parentheses are
+// described by Any or All, and have no Name.
+type Rule struct {
+	// Tag is the text reported in ValidateError
+	Tag   string
+	Name  string
+	Param string
+	// Expanded is the underlying rule if Name is an alias
+	Expanded string
+	Negate   bool
+
+	Any []Rule
+	All []Rule
+}
+
+func (v *Validator) describeStruct(rule *structRule) *StructRules {
+	sr := &StructRules{
+		Name:   rule.structName,
+		Type:   rule.structType,
+		Fields: make([]FieldRules, len(rule.fields)),
+	}
+	for i, field := range rule.fields {
+		sr.Fields[i] = FieldRules{
+			Name:  field.Name,
+			Type:  field.Type,
+			Rules: describeRules(rule.validateFunc[i]),
+		}
+		if key := rule.nestedKeys[i]; key != "" {
+			if nested := v.loadRuleKey(key); nested != nil {
+				sr.Fields[i].Nested = v.describeStruct(nested)
+			}
+		}
+	}
+	return sr
+}
+
+func describeRules(fs []*validateFn) []Rule {
+	if fs == nil {
+		return nil
+	}
+	rules := make([]Rule, len(fs))
+	for i, f := range fs {
+		rules[i] = Rule{
+			Tag:      f.tag,
+			Name:     f.name,
+			Param:    f.param,
+			Expanded: f.expanded,
+			Negate:   f.negate,
+			Any:      describeRules(f.any),
+			All:      describeRules(f.all),
+		}
+	}
+	return rules
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// DEFAULT_TAG_NAME is the tag holding default value of field
+const DEFAULT_TAG_NAME = "default"
+
+// ApplyDefaults set zero-valued fields of s, which must be a pointer to struct,
+// to the value in `default` tag, e.g. `default:"20"`. fields of nested structs,
+// including those behind non-nil pointers, and unexported fields are set as
+// well. values of slice fields are separated by comma. default values which
+// cannot be converted into the type of field are reported as TagErrors.
+func (v *Validator) ApplyDefaults(s interface{}) error {
+	value := reflect.ValueOf(s)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrorValidateNotPointer(reflect.TypeOf(s))
+	}
+	value = value.Elem()
+
+	if errs := applyDefaults(value, value.Type().String()); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateAndDefault apply defaults to s and then validate it
+func (v *Validator) ValidateAndDefault(s interface{}) error {
+	if err := v.ApplyDefaults(s); err != nil {
+		return err
+	}
+	return v.ValidateStruct(s)
+}
+
+func applyDefaults(value reflect.Value, path string) TagErrors {
+	var errs TagErrors
+	vType := value.Type()
+	for i := 0; i < vType.NumField(); i++ {
+		fieldType := vType.Field(i)
+		field := value.Field(i)
+		if !fieldType.IsExported() {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+
+		if def, ok := fieldType.Tag.Lookup(DEFAULT_TAG_NAME); ok && field.IsZero() {
+			vals := []string{def}
+			if field.Kind() == reflect.Slice && !isTextUnmarshaler(field.Type()) {
+				vals = strings.Split(def, ",")
+			}
+			if err := setValues(field, vals); err != nil {
+				errs = append(errs, &TagError{Struct: path, Field: fieldType.Name, Tag: def, Segment: def, Err: err})
+			}
+			continue
+		}
+
+		for field.Kind() == reflect.Pointer && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct && !isTextUnmarshaler(field.Type()) {
+			errs = append(errs, applyDefaults(field, path+"."+fieldType.Name)...)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,74 @@
+package validator
+
+import "reflect"
+
+// generatedValidator validates structs without generated methods for code
+// generated by validatorgen
+var generatedValidator = New()
+
+// Dynamic is the state of Validate methods generated by validatorgen, shared by
+// nested structs. It tracks structs held by interface fields on the current
+// path like Validator.ValidateStruct, and validates those without generated
+// methods with reflection, by a Validator with default options. It's used by
+// generated code, and not meant to be used directly.
+type Dynamic struct {
+	t traversal
+}
+
+// NewDynamic return state of validating root, which is pointer to the
+// generated struct
+func NewDynamic(root interface{}) *Dynamic {
+	d := &Dynamic{}
+	d.t.root = pointerVisit(root)
+	return d
+}
+
+// Enter record pointer to struct held by interface field before it's validated
+// by generated methods, and report false if the struct is already on the path,
+// in which case it's skipped. nil pointer is not recorded.
+func (d *Dynamic) Enter(p interface{}) bool {
+	key := pointerVisit(p)
+	return key.ptr == 0 || d.t.enter(key)
+}
+
+// Leave remove pointer recorded by Enter after the struct is validated
+func (d *Dynamic) Leave(p interface{}) {
+	if key := pointerVisit(p); key.ptr != 0 {
+		delete(d.t.visited, key)
+	}
+}
+
+// pointerVisit return the visit of struct p points to, or zero visit if p is
+// nil
+func pointerVisit(p interface{}) visit {
+	value := reflect.ValueOf(p)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return visit{}
+	}
+	return newVisit(value.Elem())
+}
+
+// Validate validate struct held by interface field, naming its fields under
+// levelName like Validator.ValidateStruct. value which doesn't hold a struct
+// is ignored.
+func (d *Dynamic) Validate(levelName string, value interface{}) ValidateErrors {
+	v := generatedValidator
+	field, rule := v.loadDynamicRule(reflect.ValueOf(&value).Elem(), "", &d.t)
+	if rule == nil {
+		return nil
+	}
+	key := newVisit(field)
+	if key.ptr != 0 {
+		if !d.t.enter(key) {
+			return nil
+		}
+		defer delete(d.t.visited, key)
+	}
+	return v.traverseFields(field, rule, &level{name: levelName}, &d.t)
+}
+
+// Err return the first error registering struct validated by Validate, which
+// is returned by generated Validate method instead of validation errors
+func (d *Dynamic) Err() error {
+	return d.t.err
+}
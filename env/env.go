@@ -0,0 +1,67 @@
+// Package env fills config struct from environment variables and validates it
+// with validator, so that invalid config is reported at startup all at once.
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/guan-wei-huang/validator"
+)
+
+// Error reports every invalid or missing variable, named by variable
+type Error struct {
+	Errors validator.ValidateErrors
+}
+
+func (e *Error) Error() string {
+	msg := "invalid environment:"
+	for _, err := range e.Errors {
+		msg += fmt.Sprintf("\n  %v: violate rule %v", err.Field, err.Rule)
+		if err.Expanded != "" {
+			msg += fmt.Sprintf("(%v)", err.Expanded)
+		}
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Errors
+}
+
+// Load fill cfg, which must be a pointer to struct, from environment variables
+// and validate it by v. The variable of field is named by `env` tag, or field
+// name if it's empty, and "-" skips the field. variables of nested struct are
+// prefixed by the name of the struct field, joined by "_", e.g. DB_HOST, unless
+// it's embedded without env name. slice fields are split by comma. if variable
+// is not set, `default` tag is used.
+//
+// it returns *Error if any variable is invalid or missing.
+func Load(v *validator.Validator, cfg interface{}) error {
+	return load(v, cfg, os.LookupEnv)
+}
+
+func load(v *validator.Validator, cfg interface{}, lookupEnv func(string) (string, bool)) error {
+	err := v.BindLookup(cfg, "env", "_", func(key string, field reflect.StructField) ([]string, bool) {
+		value, ok := lookupEnv(key)
+		if !ok {
+			value, ok = field.Tag.Lookup(validator.DEFAULT_TAG_NAME)
+		}
+		if !ok {
+			return nil, false
+		}
+		if field.Type.Kind() == reflect.Slice {
+			return strings.Split(value, ","), true
+		}
+		return []string{value}, true
+	})
+
+	var errs validator.ValidateErrors
+	if errors.As(err, &errs) {
+		return &Error{Errors: errs}
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guan-wei-huang/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type dbConfig struct {
+	Host string `env:"HOST" validate:"required"`
+	Port int    `env:"PORT" default:"5432" validate:"gt=0"`
+}
+
+type config struct {
+	Mode    string        `env:"MODE" default:"dev" validate:"oneof=dev prod"`
+	Workers int           `env:"WORKERS" validate:"required,gt=0"`
+	Hosts   []string      `env:"HOSTS" validate:"omitempty,len=2"`
+	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+	DB      dbConfig      `env:"DB"`
+}
+
+func mapEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	v := validator.New()
+
+	var cfg config
+	err := load(v, &cfg, mapEnv(map[string]string{
+		"WORKERS": "4",
+		"HOSTS":   "a,b",
+		"DB_HOST": "localhost",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, config{
+		Mode:    "dev",
+		Workers: 4,
+		Hosts:   []string{"a", "b"},
+		Timeout: 5 * time.Second,
+		DB:      dbConfig{Host: "localhost", Port: 5432},
+	}, cfg)
+
+	cfg = config{}
+	err = load(v, &cfg, mapEnv(map[string]string{
+		"MODE":    "test",
+		"DB_PORT": "x",
+	}))
+	var envErr *Error
+	assert.ErrorAs(t, err, &envErr)
+	assert.Equal(t, "invalid environment:\n"+
+		"  DB_PORT: violate rule type=int\n"+
+		"  MODE: violate rule oneof=dev prod\n"+
+		"  WORKERS: violate rule required\n"+
+		"  WORKERS: violate rule gt=0\n"+
+		"  DB_HOST: violate rule required", err.Error())
+
+	var validateErrs validator.ValidateErrors
+	assert.ErrorAs(t, err, &validateErrs)
+	assert.Len(t, validateErrs, 5)
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	type logConfig struct {
+		Level string `env:"LEVEL" default:"info" validate:"oneof=debug info"`
+	}
+	type appConfig struct {
+		logConfig
+		Name string `env:"NAME" validate:"required"`
+	}
+
+	var cfg appConfig
+	err := load(validator.New(), &cfg, mapEnv(map[string]string{"NAME": "app"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "info", cfg.Level)
+
+	err = load(validator.New(), &cfg, mapEnv(map[string]string{"NAME": "app", "LEVEL": "trace"}))
+	assert.EqualError(t, err, "invalid environment:\n  LEVEL: violate rule oneof=debug info")
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Setenv("WORKERS", "2")
+	t.Setenv("DB_HOST", "localhost")
+
+	var cfg config
+	assert.NoError(t, Load(validator.New(), &cfg))
+	assert.Equal(t, 2, cfg.Workers)
+	assert.EqualError(t, Load(validator.New(), cfg), validator.ErrorValidateWrongType("pointer to struct").Error())
+}
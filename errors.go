@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 func ErrorValidateInvalidTag(toType reflect.Kind, str string) error {
@@ -13,17 +14,60 @@ func ErrorValidateWrongType(expect string) error {
 	return fmt.Errorf("invalid validation error, expect value type: %v, but got nil", expect)
 }
 
+// ErrorValidateNotPointer reports a value passed to a call which modifies it
+func ErrorValidateNotPointer(got reflect.Type) error {
+	return fmt.Errorf("cannot modify value of type %v, expect pointer to struct", got)
+}
+
+// ErrorValidateModifierType reports a modifier on field of unsupported type
+func ErrorValidateModifierType(mod string, got reflect.Type) error {
+	return fmt.Errorf("modifier %v cannot apply to type %v", mod, got)
+}
+
 func ErrorValidateUnsupportedTag(tag string) error {
 	return fmt.Errorf("got unsupported tag: %v", tag)
 }
 
+func ErrorValidateInvalidAlias(alias string) error {
+	return fmt.Errorf("invalid alias name: %v", alias)
+}
+
+func ErrorValidateAlias(alias, tag string, err error) error {
+	return fmt.Errorf("alias %v(%v): %w", alias, tag, err)
+}
+
+func ErrorValidateAliasCycle(path []string) error {
+	return fmt.Errorf("alias cycle detected: %v", strings.Join(path, " -> "))
+}
+
+func ErrorValidateUnknownKey(key, suggestion string) error {
+	if suggestion == "" {
+		return fmt.Errorf("unknown field: %v", key)
+	}
+	return fmt.Errorf("unknown field: %v, did you mean %v?", key, suggestion)
+}
+
+func ErrorValidateEmptyPath(path string) error {
+	return fmt.Errorf("empty field in path: %q", path)
+}
+
+// ValidateError reports a field violating Rule. if Rule is an alias, Expanded is
+// the underlying rule that failed.
 type ValidateError struct {
-	Field string
-	Rule  string
+	Field    string
+	Rule     string
+	Expanded string
+}
+
+func (e ValidateError) rule() string {
+	if e.Expanded == "" {
+		return e.Rule
+	}
+	return fmt.Sprintf("%v(%v)", e.Rule, e.Expanded)
 }
 
 func (e ValidateError) Error() string {
-	return fmt.Sprintf("validation failed, field: %v, violate rule: %v", e.Field, e.Rule)
+	return fmt.Sprintf("validation failed, field: %v, violate rule: %v", e.Field, e.rule())
 }
 
 type ValidateErrors []ValidateError
@@ -34,11 +78,88 @@ func (e ValidateErrors) Error() string {
 	}
 	msg := "validation failed:\n"
 	for i, v := range e {
-		msg += fmt.Sprintf("%v: field[%v], violate rule[%v]\n", i, v.Field, v.Rule)
+		msg += fmt.Sprintf("%v: field[%v], violate rule[%v]\n", i, v.Field, v.rule())
 	}
 	return msg
 }
 
 func ErrorValidateFalse(field, rule string) ValidateError {
-	return ValidateError{field, rule}
+	return ValidateError{Field: field, Rule: rule}
+}
+
+// ErrorValidateMapType reports a value which should be an object of nested rules
+func ErrorValidateMapType(field string) ValidateError {
+	return ValidateError{Field: field, Rule: "object"}
+}
+
+// ErrorValidateUnexpectedKey reports a key of data which has no rule
+func ErrorValidateUnexpectedKey(field string) ValidateError {
+	return ValidateError{Field: field, Rule: "unexpected"}
+}
+
+// ErrorValidateConversion reports an input value which cannot be converted into
+// the type of field
+func ErrorValidateConversion(field, fieldType string) ValidateError {
+	return ValidateError{Field: field, Rule: "type=" + fieldType}
+}
+
+func ErrorValidateUnsupportedRule(rule interface{}) error {
+	return fmt.Errorf("unsupported rule type: %T", rule)
+}
+
+// TagError reports a validate rule that cannot be parsed, together with the
+// struct and field it belongs to. Index is the position of the failing segment
+// inside Tag. Line is set when the rule is loaded from file.
+type TagError struct {
+	Struct  string
+	Field   string
+	Tag     string
+	Index   int
+	Segment string
+	Line    int
+	Err     error
+}
+
+func (e *TagError) Error() string {
+	location := e.Struct
+	if e.Field != "" {
+		location += "." + e.Field
+	}
+	var msg string
+	if e.Segment == "" {
+		msg = fmt.Sprintf("invalid rule on %v: %v", location, e.Err)
+	} else {
+		msg = fmt.Sprintf("invalid tag on %v: `%v`, segment %v(%v): %v", location, e.Tag, e.Index, e.Segment, e.Err)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %v: %v", e.Line, msg)
+	}
+	return msg
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+type TagErrors []*TagError
+
+func (e TagErrors) Error() string {
+	if len(e) == 0 {
+		return ""
+	}
+	msg := "register failed:\n"
+	for i, v := range e {
+		msg += fmt.Sprintf("%v: %v\n", i, v.Error())
+	}
+	return msg
+}
+
+// withField fills the location of a tag error returned by parseTag
+func withField(err error, structPath, field string) *TagError {
+	tagErr, ok := err.(*TagError)
+	if !ok {
+		tagErr = &TagError{Err: err}
+	}
+	tagErr.Struct, tagErr.Field = structPath, field
+	return tagErr
 }
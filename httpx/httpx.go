@@ -0,0 +1,157 @@
+// Package httpx provides helpers to decode and validate JSON request body with
+// validator, and to write validation errors as JSON response.
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/guan-wei-huang/validator"
+)
+
+// DecodeError reports a request body which cannot be read or decoded
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return "invalid request body: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultMaxBodySize is the default limit of request body read by Decode
+const DefaultMaxBodySize = 1 << 20
+
+type config struct {
+	maxBodySize int64
+}
+
+// Option configures Decode and Middleware
+type Option func(*config)
+
+// WithMaxBodySize limits request body to n bytes. n <= 0 disables the limit.
+func WithMaxBodySize(n int64) Option {
+	return func(c *config) {
+		c.maxBodySize = n
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Decode decode JSON body of r into T, which must be a struct, and validate it
+// by v.ValidateJSON. body larger than DefaultMaxBodySize, or the limit set by
+// WithMaxBodySize, is not read. it returns *DecodeError if body is too large or
+// malformed, and validator.ValidateErrors if validation failed.
+func Decode[T any](r *http.Request, v *validator.Validator, opts ...Option) (T, error) {
+	return decode[T](nil, r, v, newConfig(opts))
+}
+
+// decode is Decode, which tells w to close the connection if body is too large
+func decode[T any](w http.ResponseWriter, r *http.Request, v *validator.Validator, c *config) (T, error) {
+	var body T
+	if reflect.TypeOf(body) == nil || reflect.TypeOf(body).Kind() != reflect.Struct {
+		return body, validator.ErrorValidateWrongType(reflect.Struct.String())
+	}
+	reader := r.Body
+	if c.maxBodySize > 0 {
+		reader = http.MaxBytesReader(w, r.Body, c.maxBodySize)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return body, &DecodeError{err}
+	}
+
+	if err := v.ValidateJSON(data, &body); err != nil {
+		var validateErrs validator.ValidateErrors
+		var tagErrs validator.TagErrors
+		if errors.As(err, &validateErrs) || errors.As(err, &tagErrs) {
+			return body, err
+		}
+		return body, &DecodeError{err}
+	}
+	return body, nil
+}
+
+type contextKey struct{}
+
+// Middleware decode and validate request body into T before calling next, with
+// the same options as Decode. the decoded body can be retrieved by FromContext,
+// and failed request is answered by WriteError without calling next.
+func Middleware[T any](v *validator.Validator, opts ...Option) func(http.Handler) http.Handler {
+	c := newConfig(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := decode[T](w, r, v, c)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			ctx := context.WithValue(r.Context(), contextKey{}, body)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext return the body decoded by Middleware
+func FromContext[T any](ctx context.Context) (T, bool) {
+	body, ok := ctx.Value(contextKey{}).(T)
+	return body, ok
+}
+
+// ErrorResponse is the JSON response written by WriteError
+type ErrorResponse struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field    string `json:"field"`
+	Rule     string `json:"rule"`
+	Expanded string `json:"expanded,omitempty"`
+}
+
+// WriteError write err as JSON response. validator.ValidateErrors is answered
+// with 422, *DecodeError with 400, or 413 if body is too large, and other errors
+// with 500.
+func WriteError(w http.ResponseWriter, err error) {
+	var validateErrs validator.ValidateErrors
+	var decodeErr *DecodeError
+	var maxBytesErr *http.MaxBytesError
+
+	status := http.StatusInternalServerError
+	resp := ErrorResponse{Message: http.StatusText(status)}
+	switch {
+	case errors.As(err, &validateErrs):
+		status = http.StatusUnprocessableEntity
+		resp.Message = "validation failed"
+		resp.Errors = make([]FieldError, 0, len(validateErrs))
+		for _, e := range validateErrs {
+			resp.Errors = append(resp.Errors, FieldError{e.Field, e.Rule, e.Expanded})
+		}
+
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+		resp.Message = http.StatusText(status)
+
+	case errors.As(err, &decodeErr):
+		status = http.StatusBadRequest
+		resp.Message = decodeErr.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
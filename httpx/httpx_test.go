@@ -0,0 +1,121 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guan-wei-huang/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type createUser struct {
+	Name string `json:"name" validate:"required,len=4"`
+	Age  int    `json:"age" validate:"required,gte=0"`
+}
+
+func TestDecode(t *testing.T) {
+	v := validator.New()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"john","age":0}`))
+	body, err := Decode[createUser](r, v)
+	assert.NoError(t, err)
+	assert.Equal(t, createUser{Name: "john"}, body)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"jo"}`))
+	_, err = Decode[createUser](r, v)
+	var validateErrs validator.ValidateErrors
+	assert.ErrorAs(t, err, &validateErrs)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":`))
+	_, err = Decode[createUser](r, v)
+	var decodeErr *DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	_, err = Decode[map[string]interface{}](r, v)
+	assert.EqualError(t, err, validator.ErrorValidateWrongType("struct").Error())
+}
+
+func TestMiddleware(t *testing.T) {
+	v := validator.New()
+	handler := Middleware[createUser](v)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := FromContext[createUser](r.Context())
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(body.Name))
+	}))
+
+	t.Run("success", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"john","age":3}`)))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "john", rec.Body.String())
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"jo"}`)))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp ErrorResponse
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, ErrorResponse{
+			Message: "validation failed",
+			Errors: []FieldError{
+				{Field: "/name", Rule: "len=4"},
+				{Field: "/age", Rule: "required"},
+			},
+		}, resp)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[]`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestMaxBodySize(t *testing.T) {
+	v := validator.New()
+	body := `{"name":"john","age":3}`
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	_, err := Decode[createUser](r, v, WithMaxBodySize(int64(len(body))))
+	assert.NoError(t, err)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	_, err = Decode[createUser](r, v, WithMaxBodySize(10))
+	var decodeErr *DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("x", DefaultMaxBodySize)+`"}`))
+	_, err = Decode[createUser](r, v)
+	var maxBytesErr *http.MaxBytesError
+	assert.ErrorAs(t, err, &maxBytesErr)
+
+	handler := Middleware[createUser](v, WithMaxBodySize(10))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t, `{"message":"Request Entity Too Large"}`, rec.Body.String())
+
+	handler = Middleware[createUser](v, WithMaxBodySize(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, validator.ErrorValidateWrongType("struct"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"message":"Internal Server Error"}`, rec.Body.String())
+}
@@ -0,0 +1,191 @@
+package gentest
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/guan-wei-huang/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// note has no generated methods, so it's validated by reflection when held by
+// interface field
+type note struct {
+	Text string `validate:"required"`
+	Geo  *Geo
+	Next any
+}
+
+type invalidNote struct {
+	Text string `validate:"len=x"`
+}
+
+func validUser() User {
+	return User{
+		ID:      "123e4567-e89b-12d3-a456-426614174000",
+		Name:    "john",
+		Email:   "john@example.com",
+		Age:     30,
+		Score:   50,
+		Level:   2,
+		Role:    "admin",
+		Code:    "TW-1",
+		Ratio:   ptr(float32(0.5)),
+		Parent:  ptr(1),
+		Deep:    ptr(ptr(uint(1))),
+		Tags:    []string{"a", "b"},
+		Pair:    [2]int{1, 0},
+		Phase:   1 + 2i,
+		Active:  true,
+		Address: Address{City: "taipei", Zip: "10001"},
+		Backup:  &Address{City: "tainan", Geo: Geo{Lat: 91}},
+		Meta:    Meta{Version: 1},
+		Audit:   &Audit{By: "admin"},
+	}
+}
+
+// assertParity check that generated Validate reports the same errors as
+// Validator.ValidateStruct
+func assertParity(t *testing.T, v *validator.Validator, u User) {
+	t.Helper()
+	expect := v.ValidateStruct(&u)
+	got := u.Validate()
+	assert.Equal(t, expect, got, "%+v", u)
+}
+
+func TestParity(t *testing.T) {
+	v := validator.New()
+
+	u := validUser()
+	u.Backup.Geo.Lat = 0
+	assert.NoError(t, u.Validate())
+	assertParity(t, v, u)
+
+	cases := []func(u *User){
+		func(u *User) { *u = User{} },
+		func(u *User) { u.ID = "x" },
+		func(u *User) { u.Name = "bob" },
+		func(u *User) { u.Email = "" },
+		func(u *User) { u.Email = "John <john@example.com>" },
+		func(u *User) { u.Age, u.Score = 17, -1.5 },
+		func(u *User) { u.Score = math.NaN() },
+		func(u *User) { u.Level = 4 },
+		func(u *User) { u.Role = "root" },
+		func(u *User) { u.Code = "tw" },
+		func(u *User) { u.Ratio = nil },
+		func(u *User) { u.Ratio = ptr(float32(1.5)) },
+		func(u *User) { u.Parent = nil },
+		func(u *User) { u.Parent = ptr(0) },
+		func(u *User) { u.Deep = nil },
+		func(u *User) { u.Deep = new(*uint) },
+		func(u *User) { u.Deep = ptr(ptr(uint(0))) },
+		func(u *User) { u.Tags = nil },
+		func(u *User) { u.Pair = [2]int{} },
+		func(u *User) { u.Phase = 3 },
+		func(u *User) { u.Phase = 4 },
+		func(u *User) { u.Active = false },
+		func(u *User) { u.Address = Address{Zip: "1234a", Geo: Geo{Lat: -91, Lng: 181}} },
+		func(u *User) { u.Backup = nil },
+		func(u *User) { u.internal = 10 },
+		func(u *User) { u.Version = 0 },
+		func(u *User) { u.Audit = nil },
+		func(u *User) { u.Audit = &Audit{} },
+		func(u *User) { u.Extra = &Geo{Lat: 91} },
+		func(u *User) { u.Extra = (*Geo)(nil) },
+		func(u *User) { u.Extra = 5 },
+		func(u *User) { u.Extra = Geo{Lat: 91} },
+		func(u *User) { u.Extra = ptr(&Geo{Lat: 91}) },
+		func(u *User) { u.Extra = Address{Geo: Geo{Lng: 181}} },
+		func(u *User) { u.Extra = note{Geo: &Geo{Lat: 91}} },
+		func(u *User) { u.Extra = &note{Text: "a"} },
+		func(u *User) { u.Extra = invalidNote{} },
+	}
+	for _, c := range cases {
+		u := validUser()
+		c(&u)
+		assertParity(t, v, u)
+	}
+}
+
+func TestParityCycle(t *testing.T) {
+	v := validator.New()
+
+	u := validUser()
+	u.Name = "x"
+	u.Extra = &u
+	expect := v.ValidateStruct(&u)
+	assert.Error(t, expect)
+	assert.Equal(t, expect, u.Validate())
+
+	a, b := validUser(), User{}
+	a.Extra, b.Extra = &b, &a
+	expect = v.ValidateStruct(&a)
+	assert.Error(t, expect)
+	assert.Equal(t, expect, a.Validate())
+
+	// cycle through struct validated by reflection
+	n := &note{Next: &u}
+	u.Extra = n
+	expect = v.ValidateStruct(&u)
+	assert.Equal(t, expect, u.Validate())
+	n.Next = n
+	assert.Equal(t, v.ValidateStruct(&u), u.Validate())
+}
+
+func TestParityRandom(t *testing.T) {
+	v := validator.New()
+	r := rand.New(rand.NewSource(1))
+	pick := func(n int) int { return r.Intn(n) }
+
+	strs := []string{"", "john", "jonny", "admin", "user", "root", "TW", "TW-12", "a@b.co", "123e4567-e89b-12d3-a456-426614174000"}
+	for i := 0; i < 2000; i++ {
+		u := User{
+			ID:       strs[pick(len(strs))],
+			Name:     strs[pick(len(strs))],
+			Email:    strs[pick(len(strs))],
+			Age:      int8(pick(256) - 128),
+			Score:    float64(pick(210)-10) - 0.5,
+			Level:    uint16(pick(5)),
+			Role:     strs[pick(len(strs))],
+			Code:     strs[pick(len(strs))],
+			Tags:     make([]string, pick(3)),
+			Pair:     [2]int{pick(2), 0},
+			Phase:    complex(float64(pick(5)), float64(pick(3))),
+			Active:   pick(2) == 0,
+			internal: pick(20),
+			Address: Address{
+				City: strs[pick(2)],
+				Zip:  []string{"", "12345", "1234", "abcde"}[pick(4)],
+				Geo:  Geo{Lat: float64(pick(200) - 100), Lng: float64(pick(400) - 200)},
+			},
+		}
+		if pick(2) == 0 {
+			u.Ratio = ptr(float32(pick(4)) / 2)
+		}
+		if pick(2) == 0 {
+			u.Parent = ptr(pick(3) - 1)
+		}
+		switch pick(3) {
+		case 1:
+			u.Deep = new(*uint)
+		case 2:
+			u.Deep = ptr(ptr(uint(pick(2))))
+		}
+		if pick(2) == 0 {
+			u.Backup = &Address{City: strs[pick(2)]}
+		}
+		u.Version = pick(2)
+		if pick(2) == 0 {
+			u.Extra = &Meta{Version: pick(2)}
+		}
+		if pick(2) == 0 {
+			u.Audit = &Audit{By: strs[pick(2)]}
+		}
+		assertParity(t, v, u)
+	}
+}
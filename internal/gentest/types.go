@@ -0,0 +1,50 @@
+// Package gentest holds structs whose Validate methods are generated by
+// validatorgen, to check that they behave the same as Validator.ValidateStruct.
+package gentest
+
+//go:generate go run ../../cmd/validatorgen -type=User
+
+type User struct {
+	ID       string   `validate:"required,uuid"`
+	Name     string   `validate:"required,len=4|len=6"`
+	Email    string   `validate:"omitempty,email"`
+	Age      int8     `validate:"gte=18,lte=120"`
+	Score    float64  `validate:"gt=-1.5,ls=100"`
+	Level    uint16   `validate:"oneof=1 2 3"`
+	Role     string   `validate:"oneof=admin user,!eq=root"`
+	Code     string   `validate:"regex='^[A-Z]{2}(-[0-9]+)?$'"`
+	Ratio    *float32 `validate:"omitempty,(gte=0,lte=1)"`
+	Parent   *int     `validate:"required,gt=0"`
+	Deep     **uint   `validate:"not eq=0"`
+	Tags     []string `validate:"len=2"`
+	Nums     []int    `validate:"min=1,max=5"`
+	Pair     [2]int   `validate:"required"`
+	Z        complex64
+	Phase    complex128 `validate:"eq=1+2i|gte=3"`
+	Active   bool       `validate:"required"`
+	Address  Address
+	Backup   *Address `validate:"omitempty"`
+	internal int      `validate:"ls=10"`
+	Extra    any
+	Meta
+	*Audit
+}
+
+type Address struct {
+	City string `validate:"required"`
+	Zip  string `validate:"omitempty,len=5,regex=^[0-9]+$"`
+	Geo  Geo
+}
+
+type Geo struct {
+	Lat float64 `validate:"gte=-90,lte=90"`
+	Lng float64 `validate:"gte=-180,lte=180"`
+}
+
+type Meta struct {
+	Version int `validate:"gt=0"`
+}
+
+type Audit struct {
+	By string `validate:"required"`
+}
@@ -0,0 +1,363 @@
+// Code generated by validatorgen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"net/mail"
+	"reflect"
+	"regexp"
+
+	"github.com/guan-wei-huang/validator"
+)
+
+var (
+	validatorgenRegex0 = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+	validatorgenRegex1 = regexp.MustCompile("^[A-Z]{2}(-[0-9]+)?$")
+	validatorgenRegex2 = regexp.MustCompile("^[0-9]+$")
+)
+
+// Validate validate s by its validate tags, the same as Validator.ValidateStruct
+func (s *User) Validate() error {
+	d := validator.NewDynamic(s)
+	errs := s.validateFields("User", d)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *User) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {
+	if s == nil {
+		return nil
+	}
+	var errs validator.ValidateErrors
+	// ID
+	{
+		if !(s.ID != "") {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".ID", "required"))
+		}
+		if !(validatorgenRegex0.MatchString(string(s.ID))) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".ID", "uuid"))
+		}
+	}
+	// Name
+	{
+		if !(s.Name != "") {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Name", "required"))
+		}
+		if !(len(s.Name) == 4 || len(s.Name) == 6) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Name", "len=4|len=6"))
+		}
+	}
+	// Email
+	if s.Email != "" {
+		if !(validatorgenIsEmail(string(s.Email))) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Email", "email"))
+		}
+	}
+	// Age
+	{
+		if !(int64(s.Age) >= 18) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Age", "gte=18"))
+		}
+		if !(int64(s.Age) <= 120) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Age", "lte=120"))
+		}
+	}
+	// Score
+	{
+		if !(float64(s.Score) > -1.5) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Score", "gt=-1.5"))
+		}
+		if !(float64(s.Score) < 100) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Score", "ls=100"))
+		}
+	}
+	// Level
+	{
+		if !(uint64(s.Level) == 1 || uint64(s.Level) == 2 || uint64(s.Level) == 3) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Level", "oneof=1 2 3"))
+		}
+	}
+	// Role
+	{
+		if !(string(s.Role) == "admin" || string(s.Role) == "user") {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Role", "oneof=admin user"))
+		}
+		if string(s.Role) == "root" {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Role", "!eq=root"))
+		}
+	}
+	// Code
+	{
+		if !(validatorgenRegex1.MatchString(string(s.Code))) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Code", "regex='^[A-Z]{2}(-[0-9]+)?$'"))
+		}
+	}
+	// Ratio
+	if s.Ratio != nil {
+		if !(s.Ratio != nil && float64(*s.Ratio) >= 0) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Ratio", "gte=0"))
+		}
+		if !(s.Ratio != nil && float64(*s.Ratio) <= 1) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Ratio", "lte=1"))
+		}
+	}
+	// Parent
+	{
+		if !(s.Parent != nil) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Parent", "required"))
+		}
+		if !(s.Parent != nil && int64(*s.Parent) > 0) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Parent", "gt=0"))
+		}
+	}
+	// Deep
+	{
+		if s.Deep != nil && *s.Deep != nil && uint64(**s.Deep) == 0 {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Deep", "not eq=0"))
+		}
+	}
+	// Tags
+	{
+		if !(len(s.Tags) == 2) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Tags", "len=2"))
+		}
+	}
+	// Pair
+	{
+		if !(!reflect.ValueOf(s.Pair).IsZero()) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Pair", "required"))
+		}
+	}
+	// Phase
+	{
+		if !(complex128(s.Phase) == complex(1, 2) || complex128(s.Phase) == complex(3, 0)) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Phase", "eq=1+2i|gte=3"))
+		}
+	}
+	// Active
+	{
+		if !(s.Active) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Active", "required"))
+		}
+	}
+	// Address
+	{
+		errs = append(errs, s.Address.validateFields(levelName+".Address", d)...)
+	}
+	// Backup
+	if s.Backup != nil {
+		if s.Backup != nil {
+			errs = append(errs, (*s.Backup).validateFields(levelName+".Backup", d)...)
+		}
+	}
+	// internal
+	{
+		if !(int64(s.internal) < 10) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".internal", "ls=10"))
+		}
+	}
+	// Extra
+	{
+		errs = append(errs, validatorgenDynamic(levelName+".Extra", s.Extra, d)...)
+	}
+	// Meta
+	{
+		errs = append(errs, s.Meta.validateFields(levelName, d)...)
+	}
+	// Audit
+	{
+		if s.Audit != nil {
+			errs = append(errs, (*s.Audit).validateFields(levelName, d)...)
+		}
+	}
+	return errs
+}
+
+// Validate validate s by its validate tags, the same as Validator.ValidateStruct
+func (s *Address) Validate() error {
+	d := validator.NewDynamic(s)
+	errs := s.validateFields("Address", d)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Address) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {
+	if s == nil {
+		return nil
+	}
+	var errs validator.ValidateErrors
+	// City
+	{
+		if !(s.City != "") {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".City", "required"))
+		}
+	}
+	// Zip
+	if s.Zip != "" {
+		if !(len(s.Zip) == 5) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Zip", "len=5"))
+		}
+		if !(validatorgenRegex2.MatchString(string(s.Zip))) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Zip", "regex=^[0-9]+$"))
+		}
+	}
+	// Geo
+	{
+		errs = append(errs, s.Geo.validateFields(levelName+".Geo", d)...)
+	}
+	return errs
+}
+
+// Validate validate s by its validate tags, the same as Validator.ValidateStruct
+func (s *Meta) Validate() error {
+	d := validator.NewDynamic(s)
+	errs := s.validateFields("Meta", d)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Meta) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {
+	if s == nil {
+		return nil
+	}
+	var errs validator.ValidateErrors
+	// Version
+	{
+		if !(int64(s.Version) > 0) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Version", "gt=0"))
+		}
+	}
+	return errs
+}
+
+// Validate validate s by its validate tags, the same as Validator.ValidateStruct
+func (s *Audit) Validate() error {
+	d := validator.NewDynamic(s)
+	errs := s.validateFields("Audit", d)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Audit) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {
+	if s == nil {
+		return nil
+	}
+	var errs validator.ValidateErrors
+	// By
+	{
+		if !(s.By != "") {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".By", "required"))
+		}
+	}
+	return errs
+}
+
+// Validate validate s by its validate tags, the same as Validator.ValidateStruct
+func (s *Geo) Validate() error {
+	d := validator.NewDynamic(s)
+	errs := s.validateFields("Geo", d)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Geo) validateFields(levelName string, d *validator.Dynamic) validator.ValidateErrors {
+	if s == nil {
+		return nil
+	}
+	var errs validator.ValidateErrors
+	// Lat
+	{
+		if !(float64(s.Lat) >= -90) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Lat", "gte=-90"))
+		}
+		if !(float64(s.Lat) <= 90) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Lat", "lte=90"))
+		}
+	}
+	// Lng
+	{
+		if !(float64(s.Lng) >= -180) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Lng", "gte=-180"))
+		}
+		if !(float64(s.Lng) <= 180) {
+			errs = append(errs, validator.ErrorValidateFalse(levelName+".Lng", "lte=180"))
+		}
+	}
+	return errs
+}
+
+func validatorgenDynamic(levelName string, value interface{}, d *validator.Dynamic) validator.ValidateErrors {
+	switch v := value.(type) {
+	case *Address:
+		if !d.Enter(v) {
+			return nil
+		}
+		defer d.Leave(v)
+		return v.validateFields(levelName, d)
+	case Address:
+		return v.validateFields(levelName, d)
+	case *Audit:
+		if !d.Enter(v) {
+			return nil
+		}
+		defer d.Leave(v)
+		return v.validateFields(levelName, d)
+	case Audit:
+		return v.validateFields(levelName, d)
+	case *Geo:
+		if !d.Enter(v) {
+			return nil
+		}
+		defer d.Leave(v)
+		return v.validateFields(levelName, d)
+	case Geo:
+		return v.validateFields(levelName, d)
+	case *Meta:
+		if !d.Enter(v) {
+			return nil
+		}
+		defer d.Leave(v)
+		return v.validateFields(levelName, d)
+	case Meta:
+		return v.validateFields(levelName, d)
+	case *User:
+		if !d.Enter(v) {
+			return nil
+		}
+		defer d.Leave(v)
+		return v.validateFields(levelName, d)
+	case User:
+		return v.validateFields(levelName, d)
+	}
+	return d.Validate(levelName, value)
+}
+
+func validatorgenIsEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
@@ -0,0 +1,405 @@
+// Package tagexpr parses validate tag into expression tree. It's shared by
+// validator and validatorgen so that both read tags the same way.
+package tagexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tag grammar:
+//
+//	tag   = expr { "," expr }
+//	expr  = term { "|" term }
+//	term  = ( "!" | "not" ) term | "(" tag ")" | rule
+//	rule  = name [ "=" param ]
+//
+// param ends at an unescaped ",", "|" or ")". A backslash escapes the next
+// character, and text wrapped in single quotes is taken literally, so
+// `regex='^[a-z]+(,[a-z]+)*$'` is a single param.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokParam
+	tokComma
+	tokPipe
+	tokLParen
+	tokRParen
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	// fields is param split by unquoted and unescaped spaces
+	fields []string
+}
+
+// tokenizer split tag into tokens. a param token is produced right after the
+// "=" following a name.
+type tokenizer struct {
+	src     string
+	pos     int
+	inParam bool
+}
+
+// IsNameChar report whether c can be used in rule name
+func IsNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (t *tokenizer) skipSpace() {
+	for t.pos < len(t.src) && t.src[t.pos] == ' ' {
+		t.pos++
+	}
+}
+
+func (t *tokenizer) next() (token, error) {
+	if t.inParam {
+		t.inParam = false
+		return t.param()
+	}
+
+	t.skipSpace()
+	start := t.pos
+	if t.pos >= len(t.src) {
+		return token{kind: tokEOF, text: "", pos: start}, nil
+	}
+
+	c := t.src[t.pos]
+	switch c {
+	case ',':
+		t.pos++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case '|':
+		t.pos++
+		return token{kind: tokPipe, text: "|", pos: start}, nil
+	case '(':
+		t.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case ')':
+		t.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case '!':
+		t.pos++
+		return token{kind: tokNot, text: "!", pos: start}, nil
+	}
+
+	if !IsNameChar(c) {
+		return token{}, syntaxError(start, "unexpected character "+string(c))
+	}
+	for t.pos < len(t.src) && IsNameChar(t.src[t.pos]) {
+		t.pos++
+	}
+	name := t.src[start:t.pos]
+	// "not" followed by space or parenthesis is negation instead of rule name
+	if name == "not" && t.pos < len(t.src) && (t.src[t.pos] == ' ' || t.src[t.pos] == '(') {
+		return token{kind: tokNot, text: name, pos: start}, nil
+	}
+	if t.pos < len(t.src) && t.src[t.pos] == '=' {
+		t.pos++
+		t.inParam = true
+	}
+	return token{kind: tokName, text: name, pos: start}, nil
+}
+
+// param read a param until unescaped ",", "|" or ")", resolving quotes and
+// backslash escapes
+func (t *tokenizer) param() (token, error) {
+	t.skipSpace()
+	start := t.pos
+	var sb strings.Builder
+	// keep is the length of param without trailing unquoted spaces
+	keep := 0
+	// field is the start of current field in sb, or -1 between fields
+	var fields []string
+	field := -1
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch c {
+		case ',', '|', ')':
+			return t.paramToken(start, sb.String()[:keep], fields, field), nil
+
+		case '\\':
+			if t.pos+1 >= len(t.src) {
+				return token{}, syntaxError(t.pos, "trailing backslash")
+			}
+			if field < 0 {
+				field = sb.Len()
+			}
+			sb.WriteByte(t.src[t.pos+1])
+			keep = sb.Len()
+			t.pos += 2
+
+		case '\'':
+			quote := t.pos
+			if field < 0 {
+				field = sb.Len()
+			}
+			t.pos++
+			for {
+				if t.pos >= len(t.src) {
+					return token{}, syntaxError(quote, "unterminated quote")
+				}
+				c := t.src[t.pos]
+				if c == '\'' {
+					t.pos++
+					break
+				}
+				if c == '\\' && t.pos+1 < len(t.src) {
+					t.pos++
+					c = t.src[t.pos]
+				}
+				sb.WriteByte(c)
+				t.pos++
+			}
+			keep = sb.Len()
+
+		case ' ':
+			if field >= 0 {
+				fields = append(fields, sb.String()[field:])
+				field = -1
+			}
+			sb.WriteByte(c)
+			t.pos++
+
+		default:
+			if field < 0 {
+				field = sb.Len()
+			}
+			sb.WriteByte(c)
+			keep = sb.Len()
+			t.pos++
+		}
+	}
+	return t.paramToken(start, sb.String()[:keep], fields, field), nil
+}
+
+// paramToken build param token, closing the last field if it's open
+func (t *tokenizer) paramToken(start int, text string, fields []string, field int) token {
+	if field >= 0 {
+		fields = append(fields, text[field:])
+	}
+	return token{kind: tokParam, text: text, pos: start, fields: fields}
+}
+
+type Kind int
+
+const (
+	Rule Kind = iota
+	And
+	Or
+)
+
+// Node is a node of parsed tag. a rule node holds Name and Param, while And
+// and Or nodes hold their operands in Children. Raw is the source text of node.
+type Node struct {
+	Kind  Kind
+	Name  string
+	Param string
+	// Fields is Param split by spaces which are neither quoted nor escaped,
+	// e.g. ["a b", "c"] for oneof='a b' c
+	Fields   []string
+	HasParam bool
+	Raw      string
+	Negate   bool
+	Children []*Node
+}
+
+// SyntaxError reports malformed tag at byte offset Pos
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at offset %v: %v", e.Pos, e.Msg)
+}
+
+// Error reports the top level segment of tag failed to parse. Index is the
+// position of the segment, and Segment is the text from it to the end of tag.
+type Error struct {
+	Index   int
+	Segment string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("segment %v(%v): %v", e.Index, e.Segment, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type tagParser struct {
+	src string
+	tok *tokenizer
+	cur token
+}
+
+// Parse parse tag into top level segments, which are separated by ",". syntax
+// error is returned as *Error pointing to the failing segment.
+func Parse(tag string) ([]*Node, error) {
+	p := &tagParser{src: tag, tok: &tokenizer{src: tag}}
+	var segments []*Node
+	segStart := 0
+	fail := func(idx int, err error) ([]*Node, error) {
+		return nil, &Error{Index: idx, Segment: strings.TrimSpace(tag[segStart:]), Err: err}
+	}
+
+	if err := p.advance(); err != nil {
+		return fail(0, err)
+	}
+	for {
+		n, err := p.expr()
+		if err != nil {
+			return fail(len(segments), err)
+		}
+
+		switch p.cur.kind {
+		case tokEOF:
+			return append(segments, n), nil
+		case tokComma:
+			segments = append(segments, n)
+			segStart = p.cur.pos + 1
+			if err := p.advance(); err != nil {
+				return fail(len(segments), err)
+			}
+		default:
+			return fail(len(segments), syntaxError(p.cur.pos, "unexpected "+p.cur.text))
+		}
+	}
+}
+
+func (p *tagParser) advance() error {
+	t, err := p.tok.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+// end return the position right after the last consumed token
+func (p *tagParser) end() int {
+	if p.cur.kind == tokEOF {
+		return len(p.src)
+	}
+	return p.cur.pos
+}
+
+func (p *tagParser) group() (*Node, error) {
+	start := p.cur.pos
+	var children []*Node
+	for {
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+		if p.cur.kind != tokComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &Node{Kind: And, Raw: strings.TrimSpace(p.src[start:p.end()]), Children: children}, nil
+}
+
+func (p *tagParser) expr() (*Node, error) {
+	start := p.cur.pos
+	n, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	if p.cur.kind != tokPipe {
+		return n, nil
+	}
+
+	or := &Node{Kind: Or, Children: []*Node{n}}
+	for p.cur.kind == tokPipe {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		or.Children = append(or.Children, n)
+	}
+	or.Raw = strings.TrimSpace(p.src[start:p.end()])
+	return or, nil
+}
+
+func (p *tagParser) term() (*Node, error) {
+	start := p.cur.pos
+	switch p.cur.kind {
+	case tokNot:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if n.Negate {
+			// wrap double negation so that each level keeps its own raw text
+			n = &Node{Kind: And, Raw: n.Raw, Children: []*Node{n}}
+		}
+		n.Negate = true
+		n.Raw = strings.TrimSpace(p.src[start:p.end()])
+		return n, nil
+
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.group()
+		if err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokRParen {
+			return nil, syntaxError(start, "unclosed parenthesis")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if n.Kind == And || n.Negate {
+			n.Raw = strings.TrimSpace(p.src[start:p.end()])
+		}
+		return n, nil
+
+	case tokName:
+		n := &Node{Kind: Rule, Name: p.cur.text}
+		if p.tok.inParam {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			n.Param, n.Fields = p.cur.text, p.cur.fields
+			n.HasParam = true
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n.Raw = strings.TrimSpace(p.src[start:p.end()])
+		return n, nil
+	}
+
+	if p.cur.kind == tokEOF {
+		return nil, syntaxError(p.cur.pos, "unexpected end of tag")
+	}
+	return nil, syntaxError(p.cur.pos, "unexpected "+p.cur.text)
+}
+
+func syntaxError(pos int, msg string) error {
+	return &SyntaxError{Pos: pos, Msg: msg}
+}
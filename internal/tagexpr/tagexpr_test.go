@@ -0,0 +1,115 @@
+package tagexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dumpNode print tag node in a compact form for comparison
+func dumpNode(n *Node) string {
+	if n.Negate {
+		m := *n
+		m.Negate = false
+		return "!" + dumpNode(&m)
+	}
+	switch n.Kind {
+	case And, Or:
+		sep := ","
+		if n.Kind == Or {
+			sep = "|"
+		}
+		s := "("
+		for i, c := range n.Children {
+			if i > 0 {
+				s += sep
+			}
+			s += dumpNode(c)
+		}
+		return s + ")"
+	}
+	if n.Param == "" {
+		return n.Name
+	}
+	return n.Name + "[" + n.Param + "]"
+}
+
+func TestParseTagExpr(t *testing.T) {
+	cases := []struct {
+		tag    string
+		expect []string
+		raw    []string
+	}{
+		{"required,len=5", []string{"required", "len[5]"}, []string{"required", "len=5"}},
+		{"gt=1 , ls=3", []string{"gt[1]", "ls[3]"}, []string{"gt=1", "ls=3"}},
+		{`regex='^a,b|c$'`, []string{"regex[^a,b|c$]"}, []string{`regex='^a,b|c$'`}},
+		{`regex=a\,b\)`, []string{"regex[a,b)]"}, []string{`regex=a\,b\)`}},
+		{`regex='it\'s'`, []string{"regex[it's]"}, []string{`regex='it\'s'`}},
+		{"eq=a=b", []string{"eq[a=b]"}, []string{"eq=a=b"}},
+		{"oneof=a b c|len=0", []string{"(oneof[a b c]|len[0])"}, []string{"oneof=a b c|len=0"}},
+		{"(gt=1,ls=5)|eq=0,required", []string{"((gt[1],ls[5])|eq[0])", "required"}, []string{"(gt=1,ls=5)|eq=0", "required"}},
+		{"(required)", []string{"required"}, []string{"required"}},
+		{"!eq=0", []string{"!eq[0]"}, []string{"!eq=0"}},
+		{"!!eq=0", []string{"!(!eq[0])"}, []string{"!!eq=0"}},
+		{"not (eq=0|eq=1),len=2", []string{"!(eq[0]|eq[1])", "len[2]"}, []string{"not (eq=0|eq=1)", "len=2"}},
+		{"not(eq=1,ls=0)|email", []string{"(!(eq[1],ls[0])|email)"}, []string{"not(eq=1,ls=0)|email"}},
+		{"note=x", []string{"note[x]"}, []string{"note=x"}},
+	}
+
+	for _, c := range cases {
+		segments, err := Parse(c.tag)
+		if !assert.NoError(t, err, c.tag) {
+			continue
+		}
+		var got, raw []string
+		for _, n := range segments {
+			got = append(got, dumpNode(n))
+			raw = append(raw, n.Raw)
+		}
+		assert.Equal(t, c.expect, got, c.tag)
+		assert.Equal(t, c.raw, raw, c.tag)
+	}
+}
+
+func TestParseTagExprFields(t *testing.T) {
+	cases := []struct {
+		tag    string
+		fields []string
+	}{
+		{"oneof=a b  c ", []string{"a", "b", "c"}},
+		{"oneof='a b' c", []string{"a b", "c"}},
+		{`oneof=a\ b 'c'd '' e`, []string{"a b", "cd", "", "e"}},
+		{"oneof=", nil},
+	}
+
+	for _, c := range cases {
+		segments, err := Parse(c.tag)
+		if assert.NoError(t, err, c.tag) {
+			assert.Equal(t, c.fields, segments[0].Fields, c.tag)
+		}
+	}
+}
+
+func TestParseTagExprSyntaxError(t *testing.T) {
+	cases := []struct {
+		tag   string
+		index int
+		err   string
+	}{
+		{"required,", 1, syntaxError(9, "unexpected end of tag").Error()},
+		{"len=4,(gt=1", 1, syntaxError(6, "unclosed parenthesis").Error()},
+		{"regex='abc", 0, syntaxError(6, "unterminated quote").Error()},
+		{"gt=1)", 0, syntaxError(4, "unexpected )").Error()},
+		{"required,#", 1, syntaxError(9, "unexpected character #").Error()},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.tag)
+		var tagErr *Error
+		if !assert.ErrorAs(t, err, &tagErr, c.tag) {
+			continue
+		}
+		assert.Equal(t, c.index, tagErr.Index, c.tag)
+		assert.EqualError(t, tagErr.Err, c.err, c.tag)
+	}
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// ValidateJSON decode data into s, which must be a pointer to struct, and
+// validate it with awareness of which keys are present in data: required means
+// the key is present, even if its value is zero, and rules of a field with
+// omitempty are skipped when the key is absent. failed fields are reported by
+// JSON pointer, e.g. /address/city.
+func (v *Validator) ValidateJSON(data []byte, s interface{}) error {
+	value := reflect.ValueOf(s)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrorValidateWrongType("pointer to struct")
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+
+	present := make(map[string]bool)
+	v.collectPresence(data, value.Elem().Type(), "", present)
+	return v.validateStruct(s, "", &traversal{present: present, naming: naming{tag: "json", pointer: true}})
+}
+
+// collectPresence record dotted path of fields which can be decoded, and
+// whether their key is present in data and not null. it reports whether any
+// key of fields is present.
+func (v *Validator) collectPresence(data []byte, sType reflect.Type, path string, present map[string]bool) bool {
+	var obj map[string]json.RawMessage
+	if data != nil {
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return false
+		}
+	}
+
+	found := false
+	for i := 0; i < sType.NumField(); i++ {
+		field := sType.Field(i)
+		if !isDecodable(field) {
+			continue
+		}
+		fieldPath := joinKey(path, field.Name)
+		fieldType, _ := derefType(field.Type)
+
+		// fields of embedded struct without json name are decoded from the same
+		// object, and it's present if any of them is
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			nestedPath := fieldPath
+			if v.flatten(&field, "json") {
+				nestedPath = path
+			}
+			present[fieldPath] = v.collectPresence(data, fieldType, nestedPath, present)
+			found = found || present[fieldPath]
+			continue
+		}
+
+		name, _ := jsonName(field)
+		raw, ok := lookupKey(obj, name)
+		if ok && string(raw) == "null" {
+			ok, raw = false, nil
+		}
+		present[fieldPath] = ok
+		found = found || ok
+
+		if fieldType.Kind() == reflect.Struct {
+			v.collectPresence(raw, fieldType, fieldPath, present)
+		}
+	}
+	return found
+}
+
+// isDecodable report whether field can be set by JSON decoding. like
+// encoding/json, embedded struct is decodable even if it's unexported, since its
+// exported fields are promoted.
+func isDecodable(field reflect.StructField) bool {
+	if _, ok := jsonName(field); !ok {
+		return false
+	}
+	if fieldType, _ := derefType(field.Type); field.Anonymous && fieldType.Kind() == reflect.Struct {
+		return true
+	}
+	return field.IsExported()
+}
+
+// lookupKey find key in obj, matching case-insensitively like encoding/json if
+// there is no exact match
+func lookupKey(obj map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	if raw, ok := obj[key]; ok {
+		return raw, true
+	}
+	for k, raw := range obj {
+		if strings.EqualFold(k, key) {
+			return raw, true
+		}
+	}
+	return nil, false
+}
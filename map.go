@@ -0,0 +1,181 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidateMap validate dynamic payload, e.g. JSON body decoded into
+// map[string]interface{}, against rules in the same form as RegisterMapRule.
+// A key missing from data fails only if its rule is required, and a key of data
+// without rule is reported as unexpected.
+func (v *Validator) ValidateMap(data map[string]interface{}, rules map[string]interface{}) error {
+	var errors ValidateErrors
+	var tagErrs TagErrors
+	v.validateMap(data, rules, "", &errors, &tagErrs)
+
+	if len(tagErrs) > 0 {
+		return tagErrs
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (v *Validator) validateMap(data, rules map[string]interface{}, path string, errors *ValidateErrors, tagErrs *TagErrors) {
+	for _, key := range sortedKeys(rules) {
+		name := joinKey(path, key)
+		value, exist := data[key]
+
+		switch rule := rules[key].(type) {
+		case map[string]interface{}:
+			if !exist || value == nil {
+				v.validateMap(nil, rule, name, errors, tagErrs)
+				continue
+			}
+			nested, ok := value.(map[string]interface{})
+			if !ok {
+				*errors = append(*errors, ErrorValidateMapType(name))
+				continue
+			}
+			v.validateMap(nested, rule, name, errors, tagErrs)
+
+		case string:
+			if !exist || value == nil {
+				required, err := v.hasRequired(rule)
+				if err != nil {
+					*tagErrs = append(*tagErrs, withField(err, "map", name))
+				} else if required {
+					*errors = append(*errors, ErrorValidateFalse(name, "required"))
+				}
+				continue
+			}
+
+			errs, err := v.checkMapValue(coerceNumber(value), rule, name)
+			if err != nil {
+				*tagErrs = append(*tagErrs, withField(err, "map", name))
+				continue
+			}
+			*errors = append(*errors, errs...)
+
+		default:
+			*tagErrs = append(*tagErrs, withField(ErrorValidateUnsupportedRule(rule), "map", name))
+		}
+	}
+
+	for _, key := range sortedKeys(data) {
+		if _, ok := rules[key]; ok {
+			continue
+		}
+		*errors = append(*errors, ErrorValidateUnexpectedKey(joinKey(path, key)))
+	}
+}
+
+// checkMapValue check value against rule. if rule is valid but cannot apply to
+// the type of value, the value is reported with the JSON types rule applies to,
+// e.g. type=string|array. error is returned only if rule is invalid for any
+// value.
+func (v *Validator) checkMapValue(value interface{}, rule, name string) (ValidateErrors, error) {
+	rv := reflect.ValueOf(value)
+	fs, err := v.loadVarRule(rv.Type(), rule)
+	if err == nil {
+		return checkVar(rv, fs, name), nil
+	}
+
+	var names []string
+	for _, jt := range jsonTypes {
+		if _, typeErr := v.loadVarRule(jt.vType, rule); typeErr != nil {
+			continue
+		}
+		// integer is checked as float if the rule has fractional param
+		if jt.name == "number" && rv.Kind() == reflect.Int64 {
+			return v.checkMapValue(float64(rv.Int()), rule, name)
+		}
+		names = append(names, jt.name)
+	}
+	if len(names) == 0 {
+		return nil, err
+	}
+	return ValidateErrors{ErrorValidateConversion(name, strings.Join(names, "|"))}, nil
+}
+
+// jsonTypes are types of values decoded from JSON, in the order they are tried
+// when finding the type a rule applies to
+var jsonTypes = []struct {
+	name  string
+	vType reflect.Type
+}{
+	{"number", reflect.TypeOf(float64(0))},
+	{"string", reflect.TypeOf("")},
+	{"boolean", reflect.TypeOf(false)},
+	{"array", reflect.TypeOf([]interface{}{})},
+	{"object", reflect.TypeOf(map[string]interface{}{})},
+}
+
+// hasRequired report whether tag requires the value to be present
+func (v *Validator) hasRequired(tag string) (bool, error) {
+	segments, err := parseTagExpr(tag)
+	if err != nil {
+		return false, err
+	}
+	return v.nodesRequired(segments, nil), nil
+}
+
+func (v *Validator) nodesRequired(nodes []*tagNode, expanding []string) bool {
+	for _, n := range nodes {
+		if n.Negate {
+			continue
+		}
+		if n.Kind == nodeAnd && v.nodesRequired(n.Children, expanding) {
+			return true
+		}
+		if n.Kind != nodeRule || n.HasParam {
+			continue
+		}
+		if n.Name == "required" {
+			return true
+		}
+
+		tag := v.loadAlias(n.Name)
+		if tag == "" {
+			continue
+		}
+		for _, a := range expanding {
+			if a == n.Name {
+				return false
+			}
+		}
+		if segments, err := parseTagExpr(tag); err == nil && v.nodesRequired(segments, append(expanding, n.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// coerceNumber convert json.Number into int64 or float64, so that it can be
+// checked like numbers decoded as float64
+func coerceNumber(value interface{}) interface{} {
+	n, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
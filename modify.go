@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+// MOD_TAG_NAME is the tag holding modifiers of field
+const MOD_TAG_NAME = "mod"
+
+type modifyFn func(string) string
+
+// modifiers apply to string fields, pointers and slices of string
+var modTable = map[string]modifyFn{
+	"trim":           strings.TrimSpace,
+	"lower":          strings.ToLower,
+	"upper":          strings.ToUpper,
+	"title":          toTitle,
+	"squash":         squashSpace,
+	"strip_nonprint": stripNonPrint,
+}
+
+// Modify normalize string fields of s, which must be a pointer to struct, by
+// modifiers in `mod` tag applied in order, e.g. `mod:"trim,lower"`. fields of
+// nested structs, including those behind non-nil pointers, and unexported
+// fields are modified as well. unknown modifiers and modifiers on non-string
+// fields are reported as TagErrors, and nothing is modified.
+func (v *Validator) Modify(s interface{}) error {
+	value := reflect.ValueOf(s)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrorValidateNotPointer(reflect.TypeOf(s))
+	}
+	value = value.Elem()
+
+	var mods []fieldMod
+	if errs := collectMods(value, value.Type().String(), &mods); len(errs) > 0 {
+		return errs
+	}
+	for _, m := range mods {
+		modifyValue(m.field, m.fns)
+	}
+	return nil
+}
+
+// ValidateAndModify modify s and then validate it
+func (v *Validator) ValidateAndModify(s interface{}) error {
+	if err := v.Modify(s); err != nil {
+		return err
+	}
+	return v.ValidateStruct(s)
+}
+
+// fieldMod is a settable field together with its modifiers
+type fieldMod struct {
+	field reflect.Value
+	fns   []modifyFn
+}
+
+func collectMods(value reflect.Value, path string, mods *[]fieldMod) TagErrors {
+	var errs TagErrors
+	vType := value.Type()
+	for i := 0; i < vType.NumField(); i++ {
+		fieldType := vType.Field(i)
+		field := value.Field(i)
+		if !fieldType.IsExported() {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+
+		if tag, ok := fieldType.Tag.Lookup(MOD_TAG_NAME); ok {
+			fns, err := parseMods(tag, fieldType.Type)
+			if err != nil {
+				errs = append(errs, withField(err, path, fieldType.Name))
+			} else {
+				*mods = append(*mods, fieldMod{field, fns})
+			}
+			continue
+		}
+
+		for field.Kind() == reflect.Pointer && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct {
+			errs = append(errs, collectMods(field, path+"."+fieldType.Name, mods)...)
+		}
+	}
+	return errs
+}
+
+func parseMods(tag string, fieldType reflect.Type) ([]modifyFn, error) {
+	var fns []modifyFn
+	for i, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		fn, ok := modTable[name]
+		if !ok {
+			return nil, &TagError{Tag: tag, Index: i, Segment: name, Err: ErrorValidateUnsupportedTag(name)}
+		}
+		if !isStringBased(fieldType) {
+			return nil, &TagError{Tag: tag, Index: i, Segment: name, Err: ErrorValidateModifierType(name, fieldType)}
+		}
+		fns = append(fns, fn)
+	}
+	return fns, nil
+}
+
+// isStringBased report whether t is string, or pointer or slice of it
+func isStringBased(t reflect.Type) bool {
+	t, _ = derefType(t)
+	if t.Kind() == reflect.Slice {
+		t, _ = derefType(t.Elem())
+	}
+	return t.Kind() == reflect.String
+}
+
+func modifyValue(field reflect.Value, fns []modifyFn) {
+	switch field.Kind() {
+	case reflect.Pointer:
+		if !field.IsNil() {
+			modifyValue(field.Elem(), fns)
+		}
+	case reflect.Slice:
+		for i := 0; i < field.Len(); i++ {
+			modifyValue(field.Index(i), fns)
+		}
+	case reflect.String:
+		str := field.String()
+		for _, fn := range fns {
+			str = fn(str)
+		}
+		field.SetString(str)
+	}
+}
+
+// toTitle upper the first letter of each word and lower the others
+func toTitle(s string) string {
+	start := true
+	return strings.Map(func(r rune) rune {
+		upper := start
+		start = unicode.IsSpace(r)
+		if upper {
+			return unicode.ToTitle(r)
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// squashSpace replace each run of whitespace with a single space
+func squashSpace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// stripNonPrint remove characters which are not printable
+func stripNonPrint(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ValidateStructPartial validate only the given fields of s. fields are dotted
+// paths relative to s, e.g. "Address.City", and selecting a nested struct
+// selects all of its fields. a path which matches no field is reported as error.
+func (v *Validator) ValidateStructPartial(s interface{}, fields ...string) error {
+	return v.validatePartial(s, true, fields)
+}
+
+// ValidateStructExcept validate all fields of s except the given ones, which are
+// in the same form as ValidateStructPartial.
+func (v *Validator) ValidateStructExcept(s interface{}, fields ...string) error {
+	return v.validatePartial(s, false, fields)
+}
+
+func (v *Validator) validatePartial(s interface{}, only bool, fields []string) error {
+	value := deref(s)
+	if value.Kind() != reflect.Struct {
+		return ErrorValidateWrongType(reflect.Struct.String())
+	}
+	for _, path := range fields {
+		if err := v.checkFieldPath(value.Type(), path); err != nil {
+			return err
+		}
+	}
+	return v.validateStruct(s, "", &traversal{filter: newFieldFilter(only, fields)})
+}
+
+// checkFieldPath report error if dotted path matches no field of sType. path
+// through interface field is not checked since its struct is known only when
+// validating.
+func (v *Validator) checkFieldPath(sType reflect.Type, path string) error {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		if key == "" {
+			return ErrorValidateEmptyPath(path)
+		}
+		var candidates []string
+		found := v.lookupField(sType, key, &candidates)
+		if found == nil {
+			suggestion := suggestKey(key, candidates)
+			if suggestion != "" {
+				suggestion = strings.Join(append(append(keys[:i:i], suggestion), keys[i+1:]...), ".")
+			}
+			return ErrorValidateUnknownKey(path, suggestion)
+		}
+
+		fieldType, _ := derefType(found.Type)
+		switch {
+		case i == len(keys)-1 || fieldType.Kind() == reflect.Interface:
+			return nil
+		case fieldType.Kind() != reflect.Struct:
+			return ErrorValidateUnknownKey(path, "")
+		}
+		sType = fieldType
+	}
+	return nil
+}
+
+// lookupField find field of sType by name, including fields promoted from
+// embedded structs. names of visited fields are appended to candidates.
+func (v *Validator) lookupField(sType reflect.Type, name string, candidates *[]string) *reflect.StructField {
+	for i := 0; i < sType.NumField(); i++ {
+		field := sType.Field(i)
+		if v.skipField(&field) {
+			continue
+		}
+		if v.flatten(&field, "") {
+			embedded, _ := derefType(field.Type)
+			if found := v.lookupField(embedded, name, candidates); found != nil {
+				return found
+			}
+			continue
+		}
+		*candidates = append(*candidates, field.Name)
+		if field.Name == name {
+			return &field
+		}
+	}
+	return nil
+}
+
+// fieldFilter select fields to validate by dotted path. a nil filter selects
+// all fields.
+type fieldFilter struct {
+	only      bool
+	paths     map[string]bool
+	ancestors map[string]bool
+}
+
+func newFieldFilter(only bool, fields []string) *fieldFilter {
+	f := &fieldFilter{
+		only:  only,
+		paths: make(map[string]bool, len(fields)),
+		// the validated struct is ancestor of all paths, which is checked
+		// when its embedded structs are traversed
+		ancestors: map[string]bool{"": true},
+	}
+	for _, field := range fields {
+		f.paths[field] = true
+		for i := strings.LastIndexByte(field, '.'); i >= 0; i = strings.LastIndexByte(field, '.') {
+			field = field[:i]
+			f.ancestors[field] = true
+		}
+	}
+	return f
+}
+
+// selected report whether path or any of its parents is given
+func (f *fieldFilter) selected(path string) bool {
+	for {
+		if f.paths[path] {
+			return true
+		}
+		i := strings.LastIndexByte(path, '.')
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// check report whether rules of field at path should run
+func (f *fieldFilter) check(path string) bool {
+	if f == nil {
+		return true
+	}
+	return f.selected(path) == f.only
+}
+
+// descend report whether nested struct at path should be traversed
+func (f *fieldFilter) descend(path string) bool {
+	if f == nil {
+		return true
+	}
+	if f.only {
+		return f.ancestors[path] || f.selected(path)
+	}
+	return !f.selected(path)
+}
@@ -1,144 +1,538 @@
 package validator
 
 import (
+	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
+
+	"github.com/guan-wei-huang/validator/internal/tagexpr"
 )
 
 // parseTag parse tag and return slice of validateFn
-func parseTag(fieldType reflect.Type, tag string, isPtr bool) ([]*validateFn, error) {
+func (v *Validator) parseTag(fieldType reflect.Type, tag string, isPtr bool) ([]*validateFn, error) {
 	if tag == "" {
 		return nil, nil
 	}
 
-	rules := strings.Split(tag, ",")
-	fs := make([]*validateFn, 0, len(rules))
-	for _, r := range rules {
-		var vfn *validateFn
-		name, param, _ := strings.Cut(r, "=")
-		switch name {
-		case "gt", "eq", "ls":
-			p, err := parseStringToType(fieldType.Kind(), param)
+	segments, err := parseTagExpr(tag)
+	if err != nil {
+		return nil, err
+	}
+	return v.buildTag(fieldType, tag, segments, isPtr)
+}
+
+// buildTag convert parsed segments of tag into slice of validateFn
+func (v *Validator) buildTag(fieldType reflect.Type, tag string, segments []*tagNode, isPtr bool) ([]*validateFn, error) {
+	b := &tagBuilder{v: v, fieldType: fieldType, isPtr: isPtr}
+	fs := make([]*validateFn, 0, len(segments))
+	for i, n := range segments {
+		vfns, err := b.build(n)
+		if err != nil {
+			return nil, &TagError{Tag: tag, Index: i, Segment: n.Raw, Err: err}
+		}
+		fs = append(fs, vfns...)
+	}
+
+	return fs, nil
+}
+
+// tagBuilder convert parsed tag nodes of a field into validateFn, expanding
+// aliases on the way
+type tagBuilder struct {
+	v         *Validator
+	fieldType reflect.Type
+	isPtr     bool
+
+	// aliases being expanded, used to detect cycle
+	expanding []string
+}
+
+// build convert a top level tag segment into validateFn. parenthesized group
+// and alias are flattened since all of their rules must pass anyway.
+func (b *tagBuilder) build(n *tagNode) ([]*validateFn, error) {
+	if n.Kind == nodeAnd && !n.Negate {
+		var fs []*validateFn
+		for _, c := range n.Children {
+			cfs, err := b.build(c)
 			if err != nil {
 				return nil, err
 			}
-			vfn = castApplyRuleFn(name, p, r)
+			fs = append(fs, cfs...)
+		}
+		return fs, nil
+	}
+
+	if !n.Negate {
+		alias, segments, err := b.expandAlias(n)
+		if err != nil {
+			return nil, err
+		}
+		if segments != nil {
+			var fs []*validateFn
+			for _, c := range segments {
+				cfs, err := b.build(c)
+				if err != nil {
+					return nil, ErrorValidateAlias(alias, b.v.loadAlias(alias), err)
+				}
+				fs = append(fs, cfs...)
+			}
+			b.expanding = b.expanding[:len(b.expanding)-1]
 
-		case "min", "max":
-			if !isArrayBased(fieldType.Kind()) {
-				return nil, ErrorValidateUnsupportedTag(r)
+			// report the alias written in tag, and keep the underlying rule even if
+			// it's expanded from another alias
+			for _, f := range fs {
+				if f.expanded == "" {
+					f.expanded = f.tag
+				}
+				f.tag = alias
+				f.name = alias
+				f.param = ""
 			}
-			ftype := fieldType.Elem()
-			p, err := parseStringToType(ftype.Kind(), param)
+			return fs, nil
+		}
+	}
+
+	vfn, err := b.buildNode(n)
+	if err != nil {
+		return nil, err
+	}
+	return []*validateFn{vfn}, nil
+}
+
+// buildNode convert tag node into a single validateFn, combining the operands
+// of alternatives and groups
+func (b *tagBuilder) buildNode(n *tagNode) (*validateFn, error) {
+	var vfn *validateFn
+	alias, segments, err := b.expandAlias(n)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case segments != nil:
+		fs := make([]*validateFn, 0, len(segments))
+		for _, c := range segments {
+			fn, err := b.buildNode(c)
 			if err != nil {
-				return nil, err
+				return nil, ErrorValidateAlias(alias, b.v.loadAlias(alias), err)
 			}
-			vfn = castApplyRuleFn(name, p, r)
+			fs = append(fs, fn)
+		}
+		b.expanding = b.expanding[:len(b.expanding)-1]
+		vfn = &validateFn{tag: n.Raw, all: fs}
 
-		case "len":
-			p, err := parseStringToType(reflect.Int, param)
+	case n.Kind == nodeRule:
+		fn, err := newValidateFn(b.fieldType, n, b.isPtr)
+		if err != nil {
+			return nil, err
+		}
+		vfn = fn
+
+	default:
+		fs := make([]*validateFn, 0, len(n.Children))
+		for _, c := range n.Children {
+			fn, err := b.buildNode(c)
 			if err != nil {
 				return nil, err
 			}
-			vfn = castApplyRuleFn(name, p, r)
+			fs = append(fs, fn)
+		}
+		vfn = &validateFn{tag: n.Raw}
+		if n.Kind == nodeOr {
+			vfn.any = fs
+		} else {
+			vfn.all = fs
+		}
+	}
+	if n.Negate {
+		vfn.negate = true
+		vfn.tag = n.Raw
+	}
+	return vfn, nil
+}
+
+// expandAlias return parsed rules of alias if n refers to a registered alias.
+// the alias is pushed into expanding, and caller should pop it when done.
+func (b *tagBuilder) expandAlias(n *tagNode) (string, []*tagNode, error) {
+	if n.Kind != nodeRule || n.HasParam {
+		return "", nil, nil
+	}
+	tag := b.v.loadAlias(n.Name)
+	if tag == "" {
+		return "", nil, nil
+	}
+	for _, a := range b.expanding {
+		if a == n.Name {
+			return "", nil, ErrorValidateAliasCycle(append(b.expanding, n.Name))
+		}
+	}
+
+	segments, err := parseTagExpr(tag)
+	if err != nil {
+		return "", nil, ErrorValidateAlias(n.Name, tag, err)
+	}
+	b.expanding = append(b.expanding, n.Name)
+	return n.Name, segments, nil
+}
+
+// newValidateFn build validateFn for single rule, whose check is specialized for
+// the kind of fieldType
+func newValidateFn(fieldType reflect.Type, n *tagNode, isPtr bool) (*validateFn, error) {
+	check, err := newCheckFn(fieldType, n, isPtr)
+	if err != nil {
+		return nil, err
+	}
+	return &validateFn{
+		check:     check,
+		name:      n.Name,
+		param:     n.Param,
+		tag:       n.Raw,
+		omitEmpty: n.Name == "omitempty",
+		required:  n.Name == "required",
+	}, nil
+}
+
+func newCheckFn(fieldType reflect.Type, n *tagNode, isPtr bool) (checkFn, error) {
+	name, param, r := n.Name, n.Param, n.Raw
+	kind := fieldType.Kind()
+	var check checkFn
+	switch name {
+	case "gt", "eq", "ls", "gte", "lte":
+		var p interface{} = param
+		if name != "eq" || kind != reflect.String {
+			var err error
+			if p, err = parseStringToType(kind, param); err != nil {
+				return nil, err
+			}
+		}
+		check = newCompareFn(name, kind, p)
+
+	case "min", "max":
+		if !isArrayBased(kind) {
+			return nil, ErrorValidateUnsupportedTag(r)
+		}
+		if _, err := parseStringToType(fieldType.Elem().Kind(), param); err != nil {
+			return nil, err
+		}
+		// TODO
+		return alwaysPass, nil
+
+	case "len":
+		p, err := parseStringToType(reflect.Int, param)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if check, ok = newLenFn(kind, int(p.(int64))); !ok {
+			return nil, ErrorValidateUnsupportedTag(r)
+		}
 
-		case "required":
-			vfn = castApplyRuleFn(name, isPtr, r)
+	case "required":
+		return newRequiredFn(isPtr), nil
 
-		default:
+	case "omitempty":
+		// omitempty only marks the field, and is handled when traversing fields
+		return alwaysPass, nil
+
+	case "oneof":
+		var err error
+		if check, err = newOneOfFn(kind, n.Fields); err != nil {
+			return nil, err
+		}
+
+	case "email", "uuid":
+		if kind != reflect.String {
 			return nil, ErrorValidateUnsupportedTag(r)
 		}
-		fs = append(fs, vfn)
+		check = isEmail
+		if name == "uuid" {
+			check = isUUID
+		}
+
+	case "regex":
+		if kind != reflect.String {
+			return nil, ErrorValidateUnsupportedTag(r)
+		}
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return nil, err
+		}
+		check = newRegexFn(re)
+
+	default:
+		return nil, ErrorValidateUnsupportedTag(r)
 	}
 
-	return fs, nil
+	if isPtr {
+		check = skipNil(check)
+	}
+	return check, nil
+}
+
+// RegisterAlias register alias as a shorthand of tag, so that it can be used
+// like a rule, e.g. RegisterAlias("uuidid", "required,uuid"). alias should be
+// registered before the struct using it is registered or validated.
+func (v *Validator) RegisterAlias(alias, tag string) error {
+	if alias == "" || alias == "not" || strings.IndexFunc(alias, func(r rune) bool { return r > 0x7f || !tagexpr.IsNameChar(byte(r)) }) >= 0 {
+		return ErrorValidateInvalidAlias(alias)
+	}
+	if ruleNames[alias] {
+		return ErrorValidateInvalidAlias(alias)
+	}
+
+	segments, err := parseTagExpr(tag)
+	if err != nil {
+		return ErrorValidateAlias(alias, tag, err)
+	}
+	if err := v.checkAliasCycle([]string{alias}, segments); err != nil {
+		return err
+	}
+	v.aliases.Store(alias, tag)
+	return nil
+}
+
+// checkAliasCycle walk through rules and the aliases they refer to, and
+// report error if any of them refers back to an alias in path
+func (v *Validator) checkAliasCycle(path []string, nodes []*tagNode) error {
+	for _, n := range nodes {
+		if n.Kind != nodeRule {
+			if err := v.checkAliasCycle(path, n.Children); err != nil {
+				return err
+			}
+			continue
+		}
+		if n.HasParam {
+			continue
+		}
+		for _, a := range path {
+			if a == n.Name {
+				return ErrorValidateAliasCycle(append(path, n.Name))
+			}
+		}
+		tag := v.loadAlias(n.Name)
+		if tag == "" {
+			continue
+		}
+		segments, err := parseTagExpr(tag)
+		if err != nil {
+			return ErrorValidateAlias(n.Name, tag, err)
+		}
+		if err := v.checkAliasCycle(append(path, n.Name), segments); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (v *Validator) RegisterMapRule(s interface{}, ruleMap map[string]interface{}) error {
+	return v.RegisterMapRuleGroup(s, "", ruleMap)
+}
+
+// RegisterMapRuleGroup register map rule for validation group, which is used by
+// ValidateStructGroup. fields not in ruleMap keep their tag rules of group.
+func (v *Validator) RegisterMapRuleGroup(s interface{}, group string, ruleMap map[string]interface{}) error {
 	value := deref(s)
 	if value.Kind() != reflect.Struct {
 		return ErrorValidateWrongType(reflect.Struct.String())
 	}
 	vType := value.Type()
-	return v.registerMapRule(vType, ruleMap, vType.String())
+	if errs := v.registerMapRule(vType, ruleMap, vType.String(), vType.String(), group); len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
-func (v *Validator) registerMapRule(vType reflect.Type, ruleMap map[string]interface{}, ruleName string) error {
-	rule := newStructRule(ruleName, vType)
+func (v *Validator) registerMapRule(vType reflect.Type, ruleMap map[string]interface{}, ruleName, path, group string) TagErrors {
+	var errs TagErrors
+	rule := newStructRule(ruleName, group, vType)
+	// start from rules of struct tags. rules of group fall back to them for
+	// fields the map doesn't mention, like tags without `validate_<group>`.
+	if v.mergeRules || group != "" {
+		if errs := v.registerStruct(vType, ruleName, path, group); len(errs) > 0 {
+			return errs
+		}
+		copy(rule.validateFunc, v.loadRule(group, ruleName).validateFunc)
+	}
+	matched := make(map[string]bool, len(ruleMap))
+	// keys of fields of vType, which shadow the promoted ones
+	own := make(map[string]bool)
+	for i := 0; i < vType.NumField(); i++ {
+		if field := vType.Field(i); !v.skipField(&field) {
+			for _, k := range v.mapRuleKeys(field) {
+				own[k] = true
+			}
+		}
+	}
+	var candidates []string
 	for i := 0; i < vType.NumField(); i++ {
 		field := vType.Field(i)
-		fieldType := field.Type
-		fieldRule, exist := ruleMap[field.Name]
-		if !exist {
+		if v.skipField(&field) {
 			continue
 		}
+		keys := v.mapRuleKeys(field)
+		candidates = append(candidates, keys...)
+		fieldType, isPtr := derefType(field.Type)
+		var promoted []string
+		if fieldType.Kind() == reflect.Struct && v.flatten(&field, "") {
+			promoted = v.promotedKeys(fieldType)
+			candidates = append(candidates, promoted...)
+		}
 
-		isPtr := false
-		for fieldType.Kind() == reflect.Pointer {
-			isPtr = true
-			fieldType = fieldType.Elem()
+		// errors are reported with the key written in map
+		var key string
+		var fieldRule interface{}
+		for _, k := range keys {
+			if r, ok := ruleMap[k]; ok {
+				key, fieldRule = k, r
+				matched[k] = true
+				break
+			}
+		}
+		// rules of promoted fields are registered for the embedded struct field,
+		// unless they are shadowed or taken by former embedded struct. nested map
+		// of the embedded struct takes all of them.
+		if _, nestedMap := fieldRule.(map[string]interface{}); promoted != nil && !nestedMap {
+			promotedRule := make(map[string]interface{})
+			for _, k := range promoted {
+				if r, ok := ruleMap[k]; ok && !own[k] && !matched[k] {
+					promotedRule[k], matched[k] = r, true
+				}
+			}
+			// the rule is keyed by the field rather than the embedded type, so
+			// that it doesn't apply to the type elsewhere
+			if len(promotedRule) > 0 {
+				nestedName := fmt.Sprintf("%v-%v", ruleName, i)
+				errs = append(errs, v.registerMapRule(fieldType, promotedRule, nestedName, path, group)...)
+				rule.nestedKeys[i] = ruleKey(group, nestedName)
+			}
+		}
+		if key == "" {
+			continue
 		}
 
+		switch fieldRule := fieldRule.(type) {
 		// nested map rule
-		if nestedRule, ok := fieldRule.(map[string]interface{}); ok {
+		case map[string]interface{}:
+			if fieldType.Kind() != reflect.Struct {
+				errs = append(errs, withField(ErrorValidateUnsupportedRule(fieldRule), path, key))
+				continue
+			}
 			nestedName := getNestedName(fieldType, ruleName, i)
-			if err := v.registerMapRule(fieldType, nestedRule, nestedName); err != nil {
-				return err
+			errs = append(errs, v.registerMapRule(fieldType, fieldRule, nestedName, path+"."+key, group)...)
+
+		case string:
+			if fieldRule == CLEAR_RULE {
+				rule.validateFunc[i] = nil
+				continue
 			}
-		}
-		if strRule, ok := fieldRule.(string); ok {
-			fs, err := parseTag(fieldType, strRule, isPtr)
+			fs, err := v.parseTag(fieldType, fieldRule, isPtr)
 			if err != nil {
-				return err
+				errs = append(errs, withField(err, path, key))
+				continue
+			}
+			if v.mergeRules {
+				fs = mergeValidateFn(rule.validateFunc[i], fs)
 			}
 			rule.validateFunc[i] = fs
+
+		default:
+			errs = append(errs, withField(ErrorValidateUnsupportedRule(fieldRule), path, key))
 		}
 	}
 
-	v.storeRule(ruleName, rule)
+	for _, key := range sortedKeys(ruleMap) {
+		if !matched[key] {
+			errs = append(errs, withField(ErrorValidateUnknownKey(key, suggestKey(key, candidates)), path, key))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	v.storeRule(rule)
 	return nil
 }
 
+// promotedKeys return keys of fields of embedded struct type t in map rule,
+// including those promoted from structs embedded in it
+func (v *Validator) promotedKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if v.skipField(&field) {
+			continue
+		}
+		keys = append(keys, v.mapRuleKeys(field)...)
+		if fieldType, _ := derefType(field.Type); fieldType.Kind() == reflect.Struct && v.flatten(&field, "") {
+			keys = append(keys, v.promotedKeys(fieldType)...)
+		}
+	}
+	return keys
+}
+
+// mapRuleKeys return keys which can refer to field in map rule
+func (v *Validator) mapRuleKeys(field reflect.StructField) []string {
+	keys := []string{field.Name}
+	if !v.jsonKeys {
+		return keys
+	}
+	if name, ok := jsonName(field); ok && name != field.Name {
+		keys = append(keys, name)
+	}
+	return keys
+}
+
 func (v *Validator) RegisterStruct(s interface{}) error {
 	value := deref(s)
 	if value.Kind() != reflect.Struct {
 		return ErrorValidateWrongType(reflect.Struct.String())
 	}
 	vType := value.Type()
-	return v.registerStruct(vType, vType.String())
+	if errs := v.registerStruct(vType, vType.String(), vType.String(), ""); len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
-func (v *Validator) registerStruct(vType reflect.Type, ruleName string) error {
-	rule := newStructRule(ruleName, vType)
+// registerStruct parse tags of all fields, including nested struct, and collect
+// every tag error instead of stopping at the first one. path is the full path of
+// struct used in error message. for validation group, tag `validate_<group>` is
+// used if the field has one.
+func (v *Validator) registerStruct(vType reflect.Type, ruleName, path, group string) TagErrors {
+	var errs TagErrors
+	rule := newStructRule(ruleName, group, vType)
 
 	for i := 0; i < vType.NumField(); i++ {
 		field := vType.Field(i)
-		fieldType := field.Type
+		if v.skipField(&field) {
+			continue
+		}
 
 		// dereference if field is pointer
-		isPtr := false
-		for fieldType.Kind() == reflect.Pointer {
-			isPtr = true
-			fieldType = fieldType.Elem()
-		}
+		fieldType, isPtr := derefType(field.Type)
 
-		tag, _ := field.Tag.Lookup(TAG_NAME)
-		fs, err := parseTag(fieldType, tag, isPtr)
+		tag, ok := field.Tag.Lookup(TAG_NAME + "_" + group)
+		if group == "" || !ok {
+			tag = field.Tag.Get(TAG_NAME)
+		}
+		fs, err := v.parseTag(fieldType, tag, isPtr)
 		if err != nil {
-			return err
+			errs = append(errs, withField(err, path, field.Name))
 		}
 		rule.validateFunc[i] = fs
 
 		// register for nested struct
 		if fieldType.Kind() == reflect.Struct {
 			nestedName := getNestedName(fieldType, ruleName, i)
-			if err := v.registerStruct(fieldType, nestedName); err != nil {
-				return err
-			}
+			errs = append(errs, v.registerStruct(fieldType, nestedName, path+"."+field.Name, group)...)
 		}
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
 	// push into cache
-	v.storeRule(ruleName, rule)
+	v.storeRule(rule)
 	return nil
 }
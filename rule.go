@@ -1,109 +1,244 @@
 package validator
 
 import (
+	"net/mail"
 	"reflect"
+	"regexp"
 )
 
-type applyRuleFn func(vType reflect.Kind, value, param interface{}) bool
+// checkFn check value, which is dereferenced unless it's a nil pointer. it's
+// built for the kind of field when the rule is registered, so it never needs to
+// inspect the type of value.
+type checkFn func(value reflect.Value) bool
 
+// validateFn is either a single rule, or a combination of rules which passes
+// when any (alternatives) or all (group) of them pass. tag is the source text of
+// the whole combination and is reported when it fails. if tag is an alias,
+// expanded is the underlying rule.
 type validateFn struct {
-	fn    applyRuleFn
-	param interface{}
-	tag   string
+	check    checkFn
+	name     string
+	param    string
+	tag      string
+	expanded string
+
+	any    []*validateFn
+	all    []*validateFn
+	negate bool
+
+	// omitEmpty and required mark the builtin rules, which are handled when
+	// traversing fields. unlike name, they are kept if the rule is expanded
+	// from alias.
+	omitEmpty bool
+	required  bool
 }
 
-func (r validateFn) CheckPass(vType reflect.Kind, v interface{}) bool {
-	return r.fn(vType, v, r.param)
+func (r *validateFn) CheckPass(v reflect.Value) bool {
+	var pass bool
+	switch {
+	case r.any != nil:
+		for _, f := range r.any {
+			if f.CheckPass(v) {
+				pass = true
+				break
+			}
+		}
+	case r.all != nil:
+		pass = true
+		for _, f := range r.all {
+			if !f.CheckPass(v) {
+				pass = false
+				break
+			}
+		}
+	default:
+		pass = r.check(v)
+	}
+	return pass != r.negate
 }
 
-var fnTable = map[string]applyRuleFn{
-	"gt":       isGreater,
-	"eq":       isEqual,
-	"ls":       isLess,
-	"len":      isLen,
-	"required": isRequired,
-	"min":      minValue,
-	"max":      maxValue,
+// ruleNames are the builtin rules, which cannot be used as alias
+var ruleNames = map[string]bool{
+	"gt": true, "eq": true, "ls": true, "gte": true, "lte": true,
+	"len": true, "required": true, "min": true, "max": true, "oneof": true,
+	"regex": true, "email": true, "uuid": true, "omitempty": true,
 }
 
-func castApplyRuleFn(funcName string, param interface{}, tag string) *validateFn {
-	fn, ok := fnTable[funcName]
-	if !ok {
-		return nil
+// ruleName identify rule when merging rules, combination of rules is identified
+// by its whole text
+func (r validateFn) ruleName() string {
+	if r.name == "" || r.negate {
+		return r.tag
 	}
-	return &validateFn{fn, param, tag}
-}
-
-// if vType is excluded in switch case, it must be reflect.Pointer.
-// happen when the field is pointer type and user given value is a nil pointer.
-func isGreater(vType reflect.Kind, value, param interface{}) bool {
-	switch vType {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return parseToInt64(vType, value) > param.(int64)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return parseToUint64(vType, value) > param.(uint64)
-	case reflect.Float32, reflect.Float64:
-		return parseToFloat64(vType, value) > param.(float64)
+	return r.name
+}
+
+// mergeValidateFn override rules in base by rules with the same name in fs,
+// and append the others
+func mergeValidateFn(base, fs []*validateFn) []*validateFn {
+	overrides := make(map[string][]*validateFn, len(fs))
+	var order []string
+	for _, f := range fs {
+		name := f.ruleName()
+		if _, ok := overrides[name]; !ok {
+			order = append(order, name)
+		}
+		overrides[name] = append(overrides[name], f)
 	}
+
+	merged := make([]*validateFn, 0, len(base)+len(fs))
+	for _, f := range base {
+		o, ok := overrides[f.ruleName()]
+		if !ok {
+			merged = append(merged, f)
+			continue
+		}
+		// replace the first occurrence and drop the rest
+		if o != nil {
+			merged = append(merged, o...)
+			overrides[f.ruleName()] = nil
+		}
+	}
+	for _, name := range order {
+		merged = append(merged, overrides[name]...)
+	}
+	return merged
+}
+
+func alwaysPass(reflect.Value) bool {
+	return true
+}
+
+func neverPass(reflect.Value) bool {
 	return false
 }
 
-func isEqual(vType reflect.Kind, value, param interface{}) bool {
-	switch vType {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return parseToInt64(vType, value) == param.(int64)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return parseToUint64(vType, value) == param.(uint64)
-	case reflect.Float32, reflect.Float64:
-		return parseToFloat64(vType, value) == param.(float64)
-	case reflect.Complex64, reflect.Complex128:
-		return parseToComplex128(vType, value) == param.(complex128)
+// skipNil wrap check of field which is pointer, so that nil pointer fails
+// without being passed to check
+func skipNil(check checkFn) checkFn {
+	return func(v reflect.Value) bool {
+		return v.Kind() != reflect.Pointer && check(v)
 	}
-	return false
 }
 
-func isLess(vType reflect.Kind, value, param interface{}) bool {
-	switch vType {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return parseToInt64(vType, value) < param.(int64)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return parseToUint64(vType, value) < param.(uint64)
-	case reflect.Float32, reflect.Float64:
-		return parseToFloat64(vType, value) < param.(float64)
+// newCompareFn build gt, eq, ls, gte or lte for field of kind. param is already
+// parsed to the type of kind, or is the raw string for eq on string.
+func newCompareFn(name string, kind reflect.Kind, param interface{}) checkFn {
+	switch {
+	case isInt(kind):
+		return compareFn(reflect.Value.Int, name, param.(int64))
+	case isUint(kind):
+		return compareFn(reflect.Value.Uint, name, param.(uint64))
+	case isFloat(kind):
+		// NaN is neither greater, less nor equal, which the operators agree with
+		return compareFn(reflect.Value.Float, name, param.(float64))
+	case isComplex(kind):
+		// complex numbers are only equal or not
+		if name == "gt" || name == "ls" {
+			return neverPass
+		}
+		p := param.(complex128)
+		return func(v reflect.Value) bool { return v.Complex() == p }
+	case kind == reflect.String && name == "eq":
+		p := param.(string)
+		return func(v reflect.Value) bool { return v.String() == p }
 	}
-	return false
+	return neverPass
 }
 
-func isLen(vType reflect.Kind, value, param interface{}) bool {
-	size := int(param.(int64))
-	switch vType {
-	case reflect.String:
-		return len(value.(string)) == size
-	case reflect.Array:
-		return reflect.ValueOf(value).Len() == size
-	case reflect.Slice:
-		return reflect.ValueOf(value).Len() == size
+func compareFn[T int64 | uint64 | float64](get func(reflect.Value) T, name string, p T) checkFn {
+	switch name {
+	case "gt":
+		return func(v reflect.Value) bool { return get(v) > p }
+	case "ls":
+		return func(v reflect.Value) bool { return get(v) < p }
+	case "gte":
+		return func(v reflect.Value) bool { return get(v) >= p }
+	case "lte":
+		return func(v reflect.Value) bool { return get(v) <= p }
 	}
-	return false
+	return func(v reflect.Value) bool { return get(v) == p }
 }
 
-// isRequired param stores whether origin field's type is pointer or not.
-// if it's ptr, verify that value is not nil. otherwise, check that value is not
-// empty value
-func isRequired(vType reflect.Kind, value, param interface{}) bool {
-	if isPtr := param.(bool); isPtr {
-		return vType != reflect.Pointer
+// newLenFn build len for field of kind, which must be string, array, slice or
+// map
+func newLenFn(kind reflect.Kind, n int) (checkFn, bool) {
+	switch kind {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return func(v reflect.Value) bool { return v.Len() == n }, true
 	}
+	return nil, false
+}
 
-	return !reflect.ValueOf(value).IsZero()
+// newRequiredFn build required. if origin field's type is pointer, verify that
+// value is not nil. otherwise, check that value is not empty value
+func newRequiredFn(isPtr bool) checkFn {
+	if isPtr {
+		return func(v reflect.Value) bool { return v.Kind() != reflect.Pointer }
+	}
+	return func(v reflect.Value) bool { return !v.IsZero() }
 }
 
-func minValue(vType reflect.Kind, value, param interface{}) bool {
-	// TODO
-	return true
+// newOneOfFn build oneof for field of kind from candidates, which are parsed to
+// the type of kind
+func newOneOfFn(kind reflect.Kind, candidates []string) (checkFn, error) {
+	switch {
+	case kind == reflect.String:
+		return oneOfFn(reflect.Value.String, candidates), nil
+	case isInt(kind):
+		ps, err := parseAll[int64](kind, candidates)
+		return oneOfFn(reflect.Value.Int, ps), err
+	case isUint(kind):
+		ps, err := parseAll[uint64](kind, candidates)
+		return oneOfFn(reflect.Value.Uint, ps), err
+	case isFloat(kind):
+		ps, err := parseAll[float64](kind, candidates)
+		return oneOfFn(reflect.Value.Float, ps), err
+	case isComplex(kind):
+		ps, err := parseAll[complex128](kind, candidates)
+		return oneOfFn(reflect.Value.Complex, ps), err
+	}
+	if len(candidates) > 0 {
+		return nil, ErrorValidateInvalidTag(kind, candidates[0])
+	}
+	return neverPass, nil
 }
 
-func maxValue(vType reflect.Kind, value, param interface{}) bool {
-	// TODO
-	return true
+func oneOfFn[T comparable](get func(reflect.Value) T, ps []T) checkFn {
+	return func(v reflect.Value) bool {
+		x := get(v)
+		for _, p := range ps {
+			if x == p {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func parseAll[T int64 | uint64 | float64 | complex128](kind reflect.Kind, strs []string) ([]T, error) {
+	ps := make([]T, 0, len(strs))
+	for _, s := range strs {
+		p, err := parseStringToType(kind, s)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p.(T))
+	}
+	return ps, nil
+}
+
+func newRegexFn(re *regexp.Regexp) checkFn {
+	return func(v reflect.Value) bool { return re.MatchString(v.String()) }
+}
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func isUUID(v reflect.Value) bool {
+	return uuidRegex.MatchString(v.String())
+}
+
+func isEmail(v reflect.Value) bool {
+	addr, err := mail.ParseAddress(v.String())
+	return err == nil && addr.Address == v.String()
 }
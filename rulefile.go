@@ -0,0 +1,191 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegisterRulesFromJSON register map rule of s loaded from JSON, e.g.
+//
+//	{"Num": "gt=4", "Nested": {"Str": "required"}}
+//
+// errors are reported with the line of the failing rule.
+func (v *Validator) RegisterRulesFromJSON(s interface{}, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	ruleMap, lines, err := parseJSONRules(data)
+	if err != nil {
+		return err
+	}
+	return v.registerRuleFile(s, ruleMap, lines)
+}
+
+// RegisterRulesFromYAML register map rule of s loaded from YAML. It accepts the
+// same structure as RegisterRulesFromJSON.
+func (v *Validator) RegisterRulesFromYAML(s interface{}, r io.Reader) error {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	lines := make(map[string]int)
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	ruleMap, err := decodeYAMLRules(doc.Content[0], "", lines)
+	if err != nil {
+		return err
+	}
+	return v.registerRuleFile(s, ruleMap, lines)
+}
+
+// registerRuleFile register map rule, and fill the line of errors with lines,
+// which maps dotted key path to line.
+func (v *Validator) registerRuleFile(s interface{}, ruleMap map[string]interface{}, lines map[string]int) error {
+	value := deref(s)
+	if value.Kind() != reflect.Struct {
+		return ErrorValidateWrongType(reflect.Struct.String())
+	}
+	vType := value.Type()
+	root := vType.String()
+	errs := v.registerMapRule(vType, ruleMap, root, root, "")
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, e := range errs {
+		key := strings.TrimPrefix(strings.TrimPrefix(e.Struct, root), ".")
+		if key != "" {
+			key += "."
+		}
+		e.Line = lines[key+e.Field]
+	}
+	return errs
+}
+
+func parseJSONRules(data []byte) (map[string]interface{}, map[string]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lines := make(map[string]int)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, jsonLineError(data, err)
+	}
+	if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("line %v: %w", lineAt(data, dec.InputOffset()), ErrorValidateUnsupportedRule(tok))
+	}
+	ruleMap, err := decodeJSONRules(dec, data, "", lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ruleMap, lines, nil
+}
+
+// decodeJSONRules decode JSON object whose opening delimiter is consumed
+func decodeJSONRules(dec *json.Decoder, data []byte, path string, lines map[string]int) (map[string]interface{}, error) {
+	ruleMap := make(map[string]interface{})
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, jsonLineError(data, err)
+		}
+		key := tok.(string)
+		keyPath := joinKey(path, key)
+		line := lineAt(data, dec.InputOffset())
+		lines[keyPath] = line
+
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, jsonLineError(data, err)
+		}
+		switch tok := tok.(type) {
+		case string:
+			ruleMap[key] = tok
+		case json.Delim:
+			if tok != '{' {
+				return nil, &TagError{Field: keyPath, Line: line, Err: ErrorValidateUnsupportedRule([]interface{}{})}
+			}
+			nested, err := decodeJSONRules(dec, data, keyPath, lines)
+			if err != nil {
+				return nil, err
+			}
+			ruleMap[key] = nested
+		default:
+			return nil, &TagError{Field: keyPath, Line: line, Err: ErrorValidateUnsupportedRule(tok)}
+		}
+	}
+
+	// consume closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, jsonLineError(data, err)
+	}
+	return ruleMap, nil
+}
+
+func decodeYAMLRules(node *yaml.Node, path string, lines map[string]int) (map[string]interface{}, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, &TagError{Field: path, Line: node.Line, Err: ErrorValidateUnsupportedRule(yamlValue(node))}
+	}
+
+	ruleMap := make(map[string]interface{}, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		keyPath := joinKey(path, keyNode.Value)
+		lines[keyPath] = keyNode.Line
+
+		switch {
+		case valueNode.Kind == yaml.MappingNode:
+			nested, err := decodeYAMLRules(valueNode, keyPath, lines)
+			if err != nil {
+				return nil, err
+			}
+			ruleMap[keyNode.Value] = nested
+		case valueNode.Kind == yaml.ScalarNode && valueNode.Tag == "!!str":
+			ruleMap[keyNode.Value] = valueNode.Value
+		default:
+			return nil, &TagError{Field: keyPath, Line: valueNode.Line, Err: ErrorValidateUnsupportedRule(yamlValue(valueNode))}
+		}
+	}
+	return ruleMap, nil
+}
+
+// yamlValue decode node for error message
+func yamlValue(node *yaml.Node) interface{} {
+	var value interface{}
+	_ = node.Decode(&value)
+	return value
+}
+
+// jsonLineError add line to JSON syntax error
+func jsonLineError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("line %v: %w", lineAt(data, syntaxErr.Offset), err)
+	}
+	if err == io.EOF {
+		return fmt.Errorf("line %v: %w", lineAt(data, int64(len(data))), io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidateSlice validate each element of s, which is a slice or array of structs
+// or pointers to structs. failed fields are named by index of element, e.g.
+// [3].Address.City, and errors are ordered by index. nil element violates rule
+// required. elements are validated across goroutines set by WithWorkers. if ctx
+// is done before all elements are validated, its error is returned.
+func (v *Validator) ValidateSlice(ctx context.Context, s interface{}) error {
+	value := deref(s)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return ErrorValidateWrongType(reflect.Slice.String())
+	}
+	elemType, _ := derefType(value.Type().Elem())
+	if elemType.Kind() != reflect.Struct {
+		return ErrorValidateWrongType("slice of struct")
+	}
+	rule, err := v.loadStructRule(elemType, "")
+	if err != nil {
+		return err
+	}
+
+	sv := &sliceValidation{
+		v:       v,
+		done:    ctx.Done(),
+		value:   value,
+		rule:    rule,
+		results: make([]ValidateErrors, value.Len()),
+	}
+	sv.failed.Store(int64(value.Len()))
+
+	workers := v.workers
+	if workers > value.Len() {
+		workers = value.Len()
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	// errors registering dynamic types of interface fields, by worker
+	errs := make([]error, workers)
+	if workers == 1 {
+		errs[0] = sv.run()
+	} else {
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = sv.run()
+			}(i)
+		}
+		wg.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	var errors ValidateErrors
+	for _, elemErrors := range sv.results {
+		errors = append(errors, elemErrors...)
+		if v.failFast && len(errors) > 0 {
+			break
+		}
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return errors
+}
+
+// sliceValidation is shared by workers validating elements of a slice. each
+// element is taken by a single worker, which writes its errors to results.
+type sliceValidation struct {
+	v       *Validator
+	done    <-chan struct{}
+	value   reflect.Value
+	rule    *structRule
+	results []ValidateErrors
+
+	// next is the index of the next element to validate
+	next atomic.Int64
+	// failed is the lowest index of invalid element when failing fast.
+	// elements after it are not validated.
+	failed atomic.Int64
+}
+
+// run validate elements until all are taken, and return error registering
+// dynamic types
+func (sv *sliceValidation) run() error {
+	t := &traversal{}
+	for {
+		select {
+		case <-sv.done:
+			return nil
+		default:
+		}
+
+		i := sv.next.Add(1) - 1
+		if i >= int64(len(sv.results)) || i > sv.failed.Load() || t.err != nil {
+			return t.err
+		}
+		errors := sv.validate(int(i), t)
+		if len(errors) == 0 {
+			continue
+		}
+		sv.results[i] = errors
+		for sv.v.failFast {
+			failed := sv.failed.Load()
+			if i >= failed || sv.failed.CompareAndSwap(failed, i) {
+				break
+			}
+		}
+	}
+}
+
+// validate the element at index i, and prefix its errors by the index
+func (sv *sliceValidation) validate(i int, t *traversal) ValidateErrors {
+	elem := sv.value.Index(i)
+	for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Pointer {
+		return ValidateErrors{ErrorValidateFalse(indexName(i), "required")}
+	}
+
+	t.root = newVisit(elem)
+	errors := sv.v.traverseFields(elem, sv.rule, &level{}, t)
+	if len(errors) == 0 {
+		return nil
+	}
+	prefix := indexName(i)
+	for j := range errors {
+		errors[j].Field = prefix + errors[j].Field
+	}
+	return errors
+}
+
+func indexName(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
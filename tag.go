@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"github.com/guan-wei-huang/validator/internal/tagexpr"
+)
+
+// tag grammar is described in package tagexpr
+
+type tagNode = tagexpr.Node
+
+const (
+	nodeRule = tagexpr.Rule
+	nodeAnd  = tagexpr.And
+	nodeOr   = tagexpr.Or
+)
+
+// parseTagExpr parse tag into top level segments, which are separated by ",".
+// syntax error is returned as *TagError pointing to the failing segment.
+func parseTagExpr(tag string) ([]*tagNode, error) {
+	segments, err := tagexpr.Parse(tag)
+	if e, ok := err.(*tagexpr.Error); ok {
+		return nil, &TagError{Tag: tag, Index: e.Index, Segment: e.Segment, Err: e.Err}
+	}
+	return segments, err
+}
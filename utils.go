@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 func isInt(kind reflect.Kind) bool {
@@ -40,71 +41,94 @@ func parseStringToType(pType reflect.Kind, str string) (interface{}, error) {
 	return nil, ErrorValidateInvalidTag(pType, str)
 }
 
-func parseToInt64(vType reflect.Kind, value interface{}) int64 {
-	switch vType {
-	case reflect.Int:
-		return int64(value.(int))
-	case reflect.Int8:
-		return int64(value.(int8))
-	case reflect.Int16:
-		return int64(value.(int16))
-	case reflect.Int32:
-		return int64(value.(int32))
-	case reflect.Int64:
-		return value.(int64)
+// deref dereference v
+func deref(v interface{}) reflect.Value {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
 	}
-	return 0
+	return value
 }
 
-func parseToUint64(vType reflect.Kind, value interface{}) uint64 {
-	switch vType {
-	case reflect.Uint:
-		return uint64(value.(uint))
-	case reflect.Uint8:
-		return uint64(value.(uint8))
-	case reflect.Uint16:
-		return uint64(value.(uint16))
-	case reflect.Uint32:
-		return uint64(value.(uint32))
-	case reflect.Uint64:
-		return value.(uint64)
+// derefType dereference pointer type, and report whether it's pointer
+func derefType(t reflect.Type) (reflect.Type, bool) {
+	isPtr := false
+	for t.Kind() == reflect.Pointer {
+		isPtr = true
+		t = t.Elem()
 	}
-	return 0
+	return t, isPtr
 }
 
-func parseToFloat64(vType reflect.Kind, value interface{}) float64 {
-	switch vType {
-	case reflect.Float32:
-		return float64(value.(float32))
-	case reflect.Float64:
-		return value.(float64)
+func getNestedName(parentType reflect.Type, parentName string, idx int) string {
+	name := parentType.String()
+	if parentType.Name() == "" {
+		name = fmt.Sprintf("%v-%v", parentName, idx)
 	}
-	return 0
+	return name
 }
 
-func parseToComplex128(vType reflect.Kind, value interface{}) complex128 {
-	switch vType {
-	case reflect.Complex64:
-		return complex128(value.(complex64))
-	case reflect.Complex128:
-		return value.(complex128)
+// suggestKey return the candidate closest to key, matching case-insensitively
+// first and then by edit distance. it returns empty string if none is close.
+func suggestKey(key string, candidates []string) string {
+	for _, c := range candidates {
+		if strings.EqualFold(key, c) {
+			return c
+		}
+	}
+
+	best, bestDist := "", len(key)/3+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(c)); d <= bestDist && (best == "" || d < bestDist) {
+			best, bestDist = c, d
+		}
 	}
-	return 0
+	return best
 }
 
-// deref dereference v
-func deref(v interface{}) reflect.Value {
-	value := reflect.ValueOf(v)
-	for value.Kind() == reflect.Pointer && !value.IsNil() {
-		value = value.Elem()
+// editDistance return levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
 	}
-	return value
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
 }
 
-func getNestedName(parentType reflect.Type, parentName string, idx int) string {
-	name := parentType.String()
-	if parentType.Name() == "" {
-		name = fmt.Sprintf("%v-%v", parentName, idx)
+func minInt(n int, others ...int) int {
+	for _, o := range others {
+		if o < n {
+			n = o
+		}
 	}
-	return name
+	return n
+}
+
+// jsonName return the key of field in JSON, and false if field is ignored
+func jsonName(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// escapePointer escape reference token of JSON pointer
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
 }
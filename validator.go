@@ -1,117 +1,415 @@
 package validator
 
 import (
-	"fmt"
 	"reflect"
+	"strings"
 	"sync"
-	"unsafe"
 )
 
 const TAG_NAME = "validate"
 
+// CLEAR_RULE is used in map rule to remove all rules of a field
+const CLEAR_RULE = "-"
+
 type Validator struct {
 	ruleCache sync.Map
 	// ruleCache map[string]*structRule
+
+	aliases sync.Map
+	// aliases map[string]string
+
+	varCache sync.Map
+	// varCache map[varKey][]*validateFn
+
+	// match keys of map rule with json tag name as well
+	jsonKeys bool
+	// merge map rule into rules of struct tags instead of replacing them
+	mergeRules bool
+	// stop validation at the first error
+	failFast bool
+	// number of goroutines validating elements of slice
+	workers int
+	// ignore rules of unexported fields
+	skipUnexported bool
+	// name fields of embedded struct under its type name
+	namespacedEmbedded bool
+}
+
+// Option configures Validator
+type Option func(*Validator)
+
+// WithJSONKeys allows keys of map rule to refer to a field by its json tag name
+func WithJSONKeys() Option {
+	return func(v *Validator) {
+		v.jsonKeys = true
+	}
+}
+
+// WithMergeRules makes map rules, including those loaded from file, augment
+// rules of struct tags. rule in map overrides the tag rule with the same name,
+// and CLEAR_RULE removes all tag rules of the field.
+func WithMergeRules() Option {
+	return func(v *Validator) {
+		v.mergeRules = true
+	}
+}
+
+// WithFailFast stops validation at the first failed rule, so that at most one
+// error is returned. ValidateSlice returns only the error of the invalid element
+// with the lowest index.
+func WithFailFast() Option {
+	return func(v *Validator) {
+		v.failFast = true
+	}
 }
 
-func New() *Validator {
-	return &Validator{}
+// WithWorkers makes ValidateSlice validate elements across n goroutines. n <= 1
+// validates them sequentially.
+func WithWorkers(n int) Option {
+	return func(v *Validator) {
+		v.workers = n
+	}
 }
 
-// parsed validation rules for each struct
+// WithSkipUnexported ignores unexported fields, so their tags are neither parsed
+// nor validated, and nested structs held by them are not traversed. embedded
+// structs are still traversed since their exported fields are promoted.
+func WithSkipUnexported() Option {
+	return func(v *Validator) {
+		v.skipUnexported = true
+	}
+}
+
+// WithNamespacedEmbedded treats embedded struct as a regular nested struct
+// named after its type, e.g. User.BaseModel.ID, and map rules of its fields nest
+// under the type name. by default, fields of embedded struct are promoted, e.g.
+// User.ID.
+func WithNamespacedEmbedded() Option {
+	return func(v *Validator) {
+		v.namespacedEmbedded = true
+	}
+}
+
+func New(opts ...Option) *Validator {
+	v := &Validator{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// parsed validation rules for each struct. rules of a validation group are
+// cached separately from the default rules.
 type structRule struct {
 	structName string
 	structType reflect.Type
+	group      string
 
-	hasUnexported bool
-	fields        []reflect.StructField
-	validateFunc  [][]*validateFn
+	fields       []reflect.StructField
+	validateFunc [][]*validateFn
+	// nestedKeys holds cache keys of rules of nested struct fields, so that
+	// they are not built when traversing
+	nestedKeys []string
 }
 
-func newStructRule(name string, sType reflect.Type) *structRule {
-	hasUnexported := false
+func newStructRule(name, group string, sType reflect.Type) *structRule {
 	numField := sType.NumField()
 	fields := make([]reflect.StructField, numField)
+	nestedKeys := make([]string, numField)
 	for i := 0; i < numField; i++ {
 		fields[i] = sType.Field(i)
-		if !fields[i].IsExported() {
-			hasUnexported = true
+		if fieldType, _ := derefType(fields[i].Type); fieldType.Kind() == reflect.Struct {
+			nestedKeys[i] = ruleKey(group, getNestedName(fieldType, name, i))
 		}
 	}
 
 	return &structRule{
-		structName:    name,
-		structType:    sType,
-		hasUnexported: hasUnexported,
-		fields:        fields,
-		validateFunc:  make([][]*validateFn, numField),
+		structName:   name,
+		structType:   sType,
+		group:        group,
+		fields:       fields,
+		validateFunc: make([][]*validateFn, numField),
+		nestedKeys:   nestedKeys,
+	}
+}
+
+// ruleKey return the key of rule in cache
+func ruleKey(group, name string) string {
+	if group == "" {
+		return name
 	}
+	return group + ":" + name
+}
+
+func (v *Validator) loadRule(group, name string) *structRule {
+	return v.loadRuleKey(ruleKey(group, name))
 }
 
-func (v *Validator) loadRule(name string) *structRule {
-	if rule, ok := v.ruleCache.Load(name); ok {
+func (v *Validator) loadRuleKey(key string) *structRule {
+	if rule, ok := v.ruleCache.Load(key); ok {
 		return rule.(*structRule)
 	}
 	return nil
 }
 
-func (v *Validator) storeRule(name string, rule *structRule) {
-	v.ruleCache.Store(name, rule)
+func (v *Validator) storeRule(rule *structRule) {
+	v.ruleCache.Store(ruleKey(rule.group, rule.structName), rule)
+}
+
+// loadStructRule return rule of sType, and register it if cannot find rule in
+// cache
+func (v *Validator) loadStructRule(sType reflect.Type, group string) (*structRule, error) {
+	if rule := v.loadRule(group, sType.String()); rule == nil || (rule != nil && rule.structType != sType) {
+		if errs := v.registerStruct(sType, sType.String(), sType.String(), group); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+	return v.loadRule(group, sType.String()), nil
+}
+
+func (v *Validator) loadAlias(name string) string {
+	if tag, ok := v.aliases.Load(name); ok {
+		return tag.(string)
+	}
+	return ""
 }
 
 func (v *Validator) ValidateStruct(s interface{}) error {
+	return v.validateStruct(s, "", &traversal{})
+}
+
+// ValidateStructGroup validate s with rules of group, which come from tag
+// `validate_<group>` or map rule registered by RegisterMapRuleGroup. fields
+// without rule of group fall back to `validate` tag.
+func (v *Validator) ValidateStructGroup(s interface{}, group string) error {
+	return v.validateStruct(s, group, &traversal{})
+}
+
+func (v *Validator) validateStruct(s interface{}, group string, t *traversal) error {
 	value := deref(s)
 	if value.Kind() != reflect.Struct {
 		return ErrorValidateWrongType(reflect.Struct.String())
 	}
 
-	// register struct validate rule if cannot find rule in cache
 	valueType := value.Type()
-	if rule := v.loadRule(valueType.String()); rule == nil || (rule != nil && rule.structType != valueType) {
-		if err := v.registerStruct(valueType, valueType.String()); err != nil {
-			return err
+	rule, err := v.loadStructRule(valueType, group)
+	if err != nil {
+		return err
+	}
+	root := &level{name: t.naming.root(valueType)}
+	t.root = newVisit(value)
+	errors := v.traverseFields(value, rule, root, t)
+	if t.err != nil {
+		return t.err
+	}
+	if errors != nil {
+		return errors
+	}
+	return nil
+}
+
+// traversal holds the options of a single validation, shared by nested
+// structs. nil traversal validates all fields by their values.
+type traversal struct {
+	filter *fieldFilter
+	// present holds dotted paths of fields decoded from input, and whether they
+	// are present. for these fields, required means present and omitempty means
+	// absent.
+	present map[string]bool
+	naming  naming
+	// err holds the first error registering dynamic type of interface field,
+	// which is registered when traversing
+	err error
+	// root is the validated struct and visited holds structs held by interface
+	// fields on the current path, so that cyclic values are traversed once.
+	// values can only be cyclic through pointer held by interface field, since
+	// nested struct types of a rule are not recursive.
+	root    visit
+	visited map[visit]bool
+}
+
+// visit is a struct reached by pointer. type is needed since the first field
+// of struct shares its address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// newVisit return the visit of value, or zero visit if it's not addressable
+func newVisit(value reflect.Value) visit {
+	if !value.CanAddr() {
+		return visit{}
+	}
+	return visit{ptr: value.UnsafeAddr(), typ: value.Type()}
+}
+
+// enter record key on the current path, and report false if it's already on
+// it. the map is allocated here so that validation without interface field
+// doesn't allocate.
+func (t *traversal) enter(key visit) bool {
+	if key == t.root || t.visited[key] {
+		return false
+	}
+	if t.visited == nil {
+		t.visited = make(map[visit]bool)
+	}
+	t.visited[key] = true
+	return true
+}
+
+// naming names fields in error. by default, fields are named by Go field path
+// from the validated struct, e.g. User.Address.City.
+type naming struct {
+	// tag holds the key of field, e.g. json. field name is used if the tag is
+	// empty or "-".
+	tag string
+	// sep joins keys of nested fields, and the validated struct is not named
+	sep string
+	// pointer names fields by JSON pointer, e.g. /address/city
+	pointer bool
+}
+
+// root return the name of the validated struct
+func (n naming) root(sType reflect.Type) string {
+	if n.sep != "" || n.pointer {
+		return ""
+	}
+	return sType.Name()
+}
+
+// fieldName return the name of field reported in error
+func (n naming) fieldName(levelName string, field reflect.StructField) string {
+	key := field.Name
+	if n.tag != "" {
+		if name, _, _ := strings.Cut(field.Tag.Get(n.tag), ","); name != "" && name != "-" {
+			key = name
 		}
 	}
 
-	rule := v.loadRule(valueType.String())
-	if err := v.traverseFields(value, rule, valueType.Name()); err != nil {
-		return err
+	switch {
+	case n.pointer:
+		return levelName + "/" + escapePointer(key)
+	case n.sep == "":
+		return levelName + "." + key
+	case levelName == "":
+		return key
 	}
-	return nil
+	return levelName + n.sep + key
 }
 
-func (v *Validator) traverseFields(value reflect.Value, rule *structRule, levelName string) ValidateErrors {
-	var errors ValidateErrors
+// tracksPath report whether dotted paths of fields are needed
+func (t *traversal) tracksPath() bool {
+	return t.filter != nil || t.present != nil
+}
+
+// level is a struct being traversed. its name is built only when an error is
+// reported, so that passing validation doesn't allocate.
+type level struct {
+	parent *level
+	// field of parent holding the struct, nil for the validated struct
+	field *reflect.StructField
+	// name of the validated struct
+	name string
+	// path is the dotted path relative to the validated struct, set only if
+	// traversal tracks path
+	path string
+	// flat is set for embedded struct whose fields are promoted
+	flat bool
+}
 
-	if rule.hasUnexported {
-		// reallocate an opened value
-		tmp := reflect.New(value.Type()).Elem()
-		tmp.Set(value)
-		value = tmp
+func (l *level) levelName(t *traversal) string {
+	if l.parent == nil {
+		return l.name
 	}
+	if l.flat {
+		return l.parent.levelName(t)
+	}
+	return t.naming.fieldName(l.parent.levelName(t), *l.field)
+}
+
+// traverseFields validate fields of value and nested structs. unexported fields
+// are read by reflect.Value accessors, which don't need them to be exported, so
+// value is neither copied nor required to be addressable.
+func (v *Validator) traverseFields(value reflect.Value, rule *structRule, l *level, t *traversal) ValidateErrors {
+	var errors ValidateErrors
+	// declared outside of loop so that it doesn't escape
+	var nested level
+
+	for i := range rule.fields {
+		fieldType := &rule.fields[i]
+		if v.skipField(fieldType) {
+			continue
+		}
+		// fields of embedded struct are selected by promoted path, and its own
+		// rules by the path of parent, while presence is still tracked by name
+		flat := v.flatten(fieldType, t.naming.tag)
+		var fieldPath, filterPath string
+		if t.tracksPath() {
+			fieldPath = joinKey(l.path, fieldType.Name)
+			filterPath = fieldPath
+			if flat {
+				filterPath = l.path
+			}
+		}
+		check, descend := t.filter.check(filterPath), t.filter.descend(filterPath)
+		if !check && !descend {
+			continue
+		}
 
-	for i, fieldType := range rule.fields {
+		// presence is tracked only for fields which can be decoded
 		field := value.Field(i)
-		if !fieldType.IsExported() {
-			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr()))
+		fs := rule.validateFunc[i]
+		present, tracked := t.present[fieldPath]
+		if hasOmitEmpty(fs) && (tracked && !present || !tracked && field.IsZero()) {
+			continue
 		}
 
 		for field.Kind() == reflect.Pointer && !field.IsNil() {
 			field = field.Elem()
 		}
-		fieldKind := field.Kind()
-		fieldValue := field.Interface()
 
-		for _, vf := range rule.validateFunc[i] {
-			if !vf.CheckPass(fieldKind, fieldValue) {
-				name := fmt.Sprintf("%v.%v", levelName, fieldType.Name)
-				errors = append(errors, ErrorValidateFalse(name, vf.tag))
+		for _, vf := range fs {
+			if !check {
+				break
+			}
+			pass := false
+			if vf.required && tracked {
+				pass = present != vf.negate
+			} else {
+				pass = vf.CheckPass(field)
+			}
+			if !pass {
+				errors = append(errors, newValidateError(t.naming.fieldName(l.levelName(t), *fieldType), vf))
+				if v.failFast {
+					return errors
+				}
 			}
 		}
 
-		if fieldKind == reflect.Struct {
-			nestedRule := v.loadRule(getNestedName(fieldType.Type, rule.structName, i))
-			errors = append(errors, v.traverseFields(field, nestedRule, fmt.Sprintf("%v.%v", levelName, fieldType.Name))...)
+		if !descend {
+			continue
+		}
+		var nestedRule *structRule
+		var key visit
+		switch field.Kind() {
+		case reflect.Struct:
+			nestedRule = v.loadRuleKey(rule.nestedKeys[i])
+		case reflect.Interface:
+			field, nestedRule = v.loadDynamicRule(field, rule.group, t)
+			// struct already on the path is skipped like nil
+			if key = newVisit(field); nestedRule != nil && key.ptr != 0 && !t.enter(key) {
+				nestedRule = nil
+			}
+		}
+		if nestedRule != nil {
+			nested = level{parent: l, field: fieldType, path: filterPath, flat: flat}
+			errors = append(errors, v.traverseFields(field, nestedRule, &nested, t)...)
+			if key.ptr != 0 {
+				delete(t.visited, key)
+			}
+			if v.failFast && len(errors) > 0 {
+				return errors
+			}
 		}
 	}
 
@@ -120,3 +418,62 @@ func (v *Validator) traverseFields(value reflect.Value, rule *structRule, levelN
 	}
 	return errors
 }
+
+// loadDynamicRule return the struct held by interface field, dereferenced, and
+// its rule, which is registered if it's not yet. rule is nil if field doesn't
+// hold a struct, or it cannot be registered, in which case t.err is set.
+func (v *Validator) loadDynamicRule(field reflect.Value, group string, t *traversal) (reflect.Value, *structRule) {
+	if field.IsNil() {
+		return field, nil
+	}
+	field = field.Elem()
+	for field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Struct {
+		return field, nil
+	}
+	rule, err := v.loadStructRule(field.Type(), group)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return field, rule
+}
+
+// hasOmitEmpty report whether rules should be skipped for empty value
+func hasOmitEmpty(fs []*validateFn) bool {
+	for _, vf := range fs {
+		if vf.omitEmpty && !vf.negate {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten report whether fields of embedded struct field are promoted, so that
+// they are named and keyed in map rule as fields of the parent. like
+// encoding/json, embedded struct given a name by tag is not promoted.
+func (v *Validator) flatten(field *reflect.StructField, tag string) bool {
+	if v.namespacedEmbedded || !field.Anonymous {
+		return false
+	}
+	if fieldType, _ := derefType(field.Type); fieldType.Kind() != reflect.Struct {
+		return false
+	}
+	if tag == "" {
+		return true
+	}
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	return name == ""
+}
+
+// skipField report whether field is ignored
+func (v *Validator) skipField(field *reflect.StructField) bool {
+	return v.skipUnexported && !field.IsExported() && !field.Anonymous
+}
+
+func newValidateError(field string, vf *validateFn) ValidateError {
+	err := ErrorValidateFalse(field, vf.tag)
+	err.Expanded = vf.expanded
+	return err
+}
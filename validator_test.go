@@ -1,8 +1,15 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -117,6 +124,16 @@ func TestLen(t *testing.T) {
 		[]string{"TestData.Str", "TestData.FloatSlice"},
 		[]string{"len=4", "len=2"},
 	))
+
+	assert.NoError(t, validate.ValidateVar(map[string]int{"a": 1}, "len=1"))
+	assert.Error(t, validate.ValidateVar(map[string]int{}, "len=1"))
+
+	type Invalid struct {
+		Num int `validate:"len=1"`
+	}
+	var tagErrs TagErrors
+	assert.ErrorAs(t, validate.ValidateStruct(Invalid{}), &tagErrs)
+	assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnsupportedTag("len=1").Error())
 }
 
 func TestRequired(t *testing.T) {
@@ -399,6 +416,1324 @@ func TestRegisterByMap(t *testing.T) {
 			"Str": "len=4",
 			"M":   "required",
 		})
-		assert.EqualError(t, err, ErrorValidateUnsupportedTag("unsupported").Error())
+		var tagErrs TagErrors
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Len(t, tagErrs, 1)
+		assert.Equal(t, "Num", tagErrs[0].Field)
+		assert.Equal(t, 1, tagErrs[0].Index)
+		assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnsupportedTag("unsupported").Error())
+	})
+}
+
+func TestTagError(t *testing.T) {
+	type Nested struct {
+		Str string `validate:"required,lenn=4"`
+	}
+	type TestData struct {
+		Num    int    `validate:"gt=abc"`
+		Str    string `validate:"len=4"`
+		Nested Nested
+	}
+
+	validate := New()
+	err := validate.RegisterStruct(TestData{})
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 2)
+
+	assert.Equal(t, "validator.TestData", tagErrs[0].Struct)
+	assert.Equal(t, "Num", tagErrs[0].Field)
+	assert.Equal(t, "gt=abc", tagErrs[0].Tag)
+	assert.Equal(t, 0, tagErrs[0].Index)
+	assert.Equal(t, "gt=abc", tagErrs[0].Segment)
+
+	assert.Equal(t, "validator.TestData.Nested", tagErrs[1].Struct)
+	assert.Equal(t, "Str", tagErrs[1].Field)
+	assert.Equal(t, 1, tagErrs[1].Index)
+	assert.Equal(t, "lenn=4", tagErrs[1].Segment)
+	assert.EqualError(t, tagErrs[1].Err, ErrorValidateUnsupportedTag("lenn=4").Error())
+
+	// rule with tag error is not cached
+	err = validate.ValidateStruct(TestData{})
+	assert.ErrorAs(t, err, &tagErrs)
+}
+
+func TestOneOfAndRegex(t *testing.T) {
+	type TestData struct {
+		Sort  string `validate:"oneof=asc desc"`
+		Level int    `validate:"oneof=1 2 3"`
+		Tags  string `validate:"regex='^[a-z]+(,[a-z]+)*$'"`
+	}
+
+	validate := New()
+	err := validate.ValidateStruct(TestData{
+		Sort:  "asc",
+		Level: 2,
+		Tags:  "go,test",
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		Sort:  "random",
+		Level: 4,
+		Tags:  "go,,test",
+	})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Sort", "TestData.Level", "TestData.Tags"},
+		[]string{"oneof=asc desc", "oneof=1 2 3", "regex='^[a-z]+(,[a-z]+)*$'"},
+	))
+
+	t.Run("quoted", func(t *testing.T) {
+		tag := `oneof='new york' tokyo 'a,b' \  ''`
+		for _, city := range []string{"new york", "tokyo", "a,b", " ", ""} {
+			assert.NoError(t, validate.ValidateVar(city, tag), city)
+		}
+		for _, city := range []string{"new", "york", "'new", "a"} {
+			assert.Error(t, validate.ValidateVar(city, tag), city)
+		}
+	})
+}
+
+func TestAlternativeAndNegation(t *testing.T) {
+	type TestData struct {
+		Owner  string `validate:"email|eq=admin"`
+		Num    int    `validate:"!eq=0"`
+		Range  int    `validate:"(gt=10,ls=20)|eq=0"`
+		Status string `validate:"required,not oneof=deleted banned"`
+	}
+
+	validate := New()
+	err := validate.ValidateStruct(TestData{
+		Owner:  "admin",
+		Num:    3,
+		Range:  0,
+		Status: "active",
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		Owner:  "someone@example.com",
+		Num:    -1,
+		Range:  15,
+		Status: "active",
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		Owner:  "nobody",
+		Num:    0,
+		Range:  20,
+		Status: "banned",
+	})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Owner", "TestData.Num", "TestData.Range", "TestData.Status"},
+		[]string{"email|eq=admin", "!eq=0", "(gt=10,ls=20)|eq=0", "not oneof=deleted banned"},
+	))
+}
+
+func TestAlias(t *testing.T) {
+	validate := New()
+	assert.NoError(t, validate.RegisterAlias("uuidid", "required,uuid"))
+	assert.NoError(t, validate.RegisterAlias("pagesize", "gte=1,lte=100"))
+	assert.NoError(t, validate.RegisterAlias("optionalid", "len=0|uuidid"))
+
+	type TestData struct {
+		ID       string `validate:"uuidid"`
+		ParentID string `validate:"optionalid"`
+		Size     int    `validate:"pagesize"`
+		Offset   int    `validate:"!pagesize"`
+	}
+
+	err := validate.ValidateStruct(TestData{
+		ID:     "8c2a2d1e-2f4b-4b7e-9a55-6d0f3b1e2a7c",
+		Size:   20,
+		Offset: 0,
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		ID:       "",
+		ParentID: "abc",
+		Size:     101,
+		Offset:   3,
+	})
+	assert.EqualError(t, err, ValidateErrors{
+		{Field: "TestData.ID", Rule: "uuidid", Expanded: "required"},
+		{Field: "TestData.ID", Rule: "uuidid", Expanded: "uuid"},
+		{Field: "TestData.ParentID", Rule: "optionalid", Expanded: "len=0|uuidid"},
+		{Field: "TestData.Size", Rule: "pagesize", Expanded: "lte=100"},
+		{Field: "TestData.Offset", Rule: "!pagesize"},
+	}.Error())
+
+	t.Run("invalid alias", func(t *testing.T) {
+		assert.EqualError(t, validate.RegisterAlias("len", "required"), ErrorValidateInvalidAlias("len").Error())
+		assert.EqualError(t, validate.RegisterAlias("a-b", "required"), ErrorValidateInvalidAlias("a-b").Error())
+		assert.Error(t, validate.RegisterAlias("broken", "required,"))
+	})
+
+	t.Run("alias cycle", func(t *testing.T) {
+		assert.NoError(t, validate.RegisterAlias("first", "required,second"))
+		assert.NoError(t, validate.RegisterAlias("second", "len=2|third"))
+		err := validate.RegisterAlias("third", "first")
+		assert.EqualError(t, err, ErrorValidateAliasCycle([]string{"third", "first", "second", "third"}).Error())
+	})
+
+	t.Run("failed to expand", func(t *testing.T) {
+		type Case struct {
+			Num int `validate:"uuidid"`
+		}
+		err := validate.RegisterStruct(Case{})
+		var tagErrs TagErrors
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Equal(t, "uuidid", tagErrs[0].Segment)
+		assert.EqualError(t, tagErrs[0].Err, ErrorValidateAlias("uuidid", "required,uuid", ErrorValidateUnsupportedTag("uuid")).Error())
+	})
+}
+
+func TestValidateVar(t *testing.T) {
+	validate := New()
+
+	assert.NoError(t, validate.ValidateVar(20, "gte=1,lte=100"))
+	assert.NoError(t, validate.ValidateVar([]int{1, 2, 3}, "required,len=3"))
+	assert.NoError(t, validate.ValidateVar(toPtr("asc"), "required,oneof=asc desc"))
+
+	err := validate.ValidateVar(0, "gte=1,lte=100")
+	assert.EqualError(t, err, combineValidateError([]string{"int"}, []string{"gte=1"}))
+
+	var nilPtr *string
+	err = validate.ValidateVar(nilPtr, "required")
+	assert.EqualError(t, err, combineValidateError([]string{"*string"}, []string{"required"}))
+
+	err = validate.ValidateVar(nil, "required")
+	assert.EqualError(t, err, ErrorValidateWrongType("variable").Error())
+
+	err = validate.ValidateVar("str", "gt=abc")
+	var tagErr *TagError
+	assert.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, "string", tagErr.Struct)
+
+	t.Run("cached by type and tag", func(t *testing.T) {
+		assert.NoError(t, validate.ValidateVar(int8(3), "eq=3"))
+		assert.NoError(t, validate.ValidateVar(uint(3), "eq=3"))
+		assert.Error(t, validate.ValidateVar(int8(4), "eq=3"))
+	})
+}
+
+func TestValidateVarWithValue(t *testing.T) {
+	validate := New()
+
+	assert.NoError(t, validate.ValidateVarWithValue(5, 3, "gt"))
+	assert.NoError(t, validate.ValidateVarWithValue("secret", "secret", "required,eq"))
+	assert.NoError(t, validate.ValidateVarWithValue(2.5, 2.5, "gte,lte"))
+	assert.NoError(t, validate.ValidateVarWithValue([]int{1, 2}, 2, "len"))
+
+	err := validate.ValidateVarWithValue(3, 3, "gt|ls,eq=4")
+	assert.EqualError(t, err, combineValidateError([]string{"int", "int"}, []string{"gt|ls", "eq=4"}))
+}
+
+func TestValidateMap(t *testing.T) {
+	rules := map[string]interface{}{
+		"name":  "required,len=4",
+		"age":   "gt=17,ls=100",
+		"email": "email",
+		"tags":  "len=2",
+		"address": map[string]interface{}{
+			"city": "required",
+			"zip":  "len=5",
+		},
+	}
+
+	validate := New()
+	t.Run("success", func(t *testing.T) {
+		var data map[string]interface{}
+		err := json.Unmarshal([]byte(`{"name":"john","age":20,"tags":["a","b"],"address":{"city":"taipei"}}`), &data)
+		assert.NoError(t, err)
+		assert.NoError(t, validate.ValidateMap(data, rules))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		var data map[string]interface{}
+		err := json.Unmarshal([]byte(`{"age":17,"email":"x","tags":["a"],"address":{"zip":"123"},"extra":1}`), &data)
+		assert.NoError(t, err)
+		err = validate.ValidateMap(data, rules)
+		assert.EqualError(t, err, ValidateErrors{
+			{Field: "address.city", Rule: "required"},
+			{Field: "address.zip", Rule: "len=5"},
+			{Field: "age", Rule: "gt=17"},
+			{Field: "email", Rule: "email"},
+			{Field: "name", Rule: "required"},
+			{Field: "tags", Rule: "len=2"},
+			{Field: "extra", Rule: "unexpected"},
+		}.Error())
+	})
+
+	t.Run("json number and wrong nested type", func(t *testing.T) {
+		decoder := json.NewDecoder(strings.NewReader(`{"name":"john","age":200,"address":"taipei"}`))
+		decoder.UseNumber()
+		var data map[string]interface{}
+		assert.NoError(t, decoder.Decode(&data))
+		err := validate.ValidateMap(data, rules)
+		assert.EqualError(t, err, ValidateErrors{
+			ErrorValidateMapType("address"),
+			{Field: "age", Rule: "ls=100"},
+		}.Error())
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		err := validate.ValidateMap(map[string]interface{}{"age": 3.0}, map[string]interface{}{
+			"age":  "gt=abc",
+			"name": 3,
+		})
+		var tagErrs TagErrors
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Len(t, tagErrs, 2)
+		assert.Equal(t, "age", tagErrs[0].Field)
+		assert.EqualError(t, tagErrs[1].Err, ErrorValidateUnsupportedRule(3).Error())
+	})
+
+	t.Run("wrong value type", func(t *testing.T) {
+		decoder := json.NewDecoder(strings.NewReader(`{"age":"abc","code":1.0,"name":5,"score":3,"tags":["a"]}`))
+		decoder.UseNumber()
+		var data map[string]interface{}
+		assert.NoError(t, decoder.Decode(&data))
+		err := validate.ValidateMap(data, map[string]interface{}{
+			"age":   "gt=0",
+			"code":  "len=3",
+			"name":  "email",
+			"score": "gte=2.5",
+			"tags":  "len=1",
+		})
+		assert.EqualError(t, err, ValidateErrors{
+			ErrorValidateConversion("age", "number"),
+			ErrorValidateConversion("code", "string|array|object"),
+			ErrorValidateConversion("name", "string"),
+		}.Error())
+	})
+}
+
+func TestRegisterByMapUnknownKey(t *testing.T) {
+	type Nested struct {
+		City string `json:"city"`
+	}
+	type TestData struct {
+		Name    string `json:"name"`
+		Address Nested `json:"address"`
+	}
+
+	validate := New()
+	err := validate.RegisterMapRule(TestData{}, map[string]interface{}{
+		"name": "required",
+		"Adress": map[string]interface{}{
+			"Cty": "required",
+		},
+		"unrelated": "required",
+	})
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 3)
+	assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnknownKey("Adress", "Address").Error())
+	assert.EqualError(t, tagErrs[1].Err, ErrorValidateUnknownKey("name", "Name").Error())
+	assert.EqualError(t, tagErrs[2].Err, ErrorValidateUnknownKey("unrelated", "").Error())
+
+	err = validate.RegisterMapRule(TestData{}, map[string]interface{}{
+		"Address": map[string]interface{}{
+			"Cty": "required",
+		},
+	})
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 1)
+	assert.Equal(t, "validator.TestData.Address", tagErrs[0].Struct)
+	assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnknownKey("Cty", "City").Error())
+
+	t.Run("match json tag", func(t *testing.T) {
+		validate := New(WithJSONKeys())
+		err := validate.RegisterMapRule(TestData{}, map[string]interface{}{
+			"name": "required",
+			"address": map[string]interface{}{
+				"city": "len=6",
+			},
+			"unrelated": "required",
+		})
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Len(t, tagErrs, 1)
+		assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnknownKey("unrelated", "").Error())
+
+		err = validate.RegisterMapRule(TestData{}, map[string]interface{}{
+			"name": "required",
+			"address": map[string]interface{}{
+				"city": "len=6",
+			},
+		})
+		assert.NoError(t, err)
+
+		err = validate.ValidateStruct(TestData{Address: Nested{City: "taipei"}})
+		assert.EqualError(t, err, combineValidateError([]string{"TestData.Name"}, []string{"required"}))
+	})
+}
+
+func TestRegisterRulesFromFile(t *testing.T) {
+	type Nested struct {
+		Nint int
+		Nstr string
+	}
+	type TestData struct {
+		Num    int
+		Str    string
+		Nested Nested
+	}
+
+	t.Run("json", func(t *testing.T) {
+		validate := New()
+		err := validate.RegisterRulesFromJSON(TestData{}, strings.NewReader(`{
+	"Num": "gt=10",
+	"Nested": {
+		"Nint": "eq=3"
+	}
+}`))
+		assert.NoError(t, err)
+
+		err = validate.ValidateStruct(TestData{Num: 3, Nested: Nested{Nint: 2}})
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"TestData.Num", "TestData.Nested.Nint"},
+			[]string{"gt=10", "eq=3"},
+		))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		validate := New()
+		err := validate.RegisterRulesFromYAML(TestData{}, strings.NewReader(`
+Num: gt=10
+Nested:
+  Nstr: "len=2,required"
+`))
+		assert.NoError(t, err)
+
+		err = validate.ValidateStruct(TestData{Num: 11, Nested: Nested{Nstr: "abc"}})
+		assert.EqualError(t, err, combineValidateError([]string{"TestData.Nested.Nstr"}, []string{"len=2"}))
+	})
+
+	t.Run("bad rules with line", func(t *testing.T) {
+		validate := New()
+		err := validate.RegisterRulesFromJSON(TestData{}, strings.NewReader(`{
+	"Num": "gt=10",
+	"Nested": {
+		"Nint": "eq=abc",
+		"Nstrr": "required"
+	}
+}`))
+		var tagErrs TagErrors
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Len(t, tagErrs, 2)
+		assert.Equal(t, 4, tagErrs[0].Line)
+		assert.Equal(t, 5, tagErrs[1].Line)
+		assert.Contains(t, tagErrs[1].Error(), "line 5: ")
+
+		err = validate.RegisterRulesFromYAML(TestData{}, strings.NewReader(`
+Num: gt=10
+Str: lenn=3
+`))
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.Len(t, tagErrs, 1)
+		assert.Equal(t, 3, tagErrs[0].Line)
+
+		err = validate.RegisterRulesFromYAML(TestData{}, strings.NewReader(`
+Num: 10
+`))
+		var tagErr *TagError
+		assert.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, 2, tagErr.Line)
+		assert.EqualError(t, tagErr.Err, ErrorValidateUnsupportedRule(10).Error())
+
+		err = validate.RegisterRulesFromJSON(TestData{}, strings.NewReader("{\n\"Num\": \"gt=10\"\n\"Str\": \"len=3\"\n}"))
+		assert.ErrorContains(t, err, "line 3: ")
+	})
+
+	t.Run("nested rule of non-struct field", func(t *testing.T) {
+		validate := New()
+		err := validate.RegisterRulesFromJSON(TestData{}, strings.NewReader(`{
+	"Num": {
+		"x": "gt=1"
+	}
+}`))
+		var tagErrs TagErrors
+		if assert.ErrorAs(t, err, &tagErrs) {
+			assert.Equal(t, 2, tagErrs[0].Line)
+			assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnsupportedRule(map[string]interface{}{}).Error())
+		}
+
+		err = validate.RegisterRulesFromYAML(TestData{}, strings.NewReader(`
+Str: required
+Num:
+  x: gt=1
+`))
+		if assert.ErrorAs(t, err, &tagErrs) {
+			assert.Equal(t, 3, tagErrs[0].Line)
+			assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnsupportedRule(map[string]interface{}{}).Error())
+		}
+	})
+}
+
+func TestMergeMapRule(t *testing.T) {
+	type Nested struct {
+		Code string `validate:"required,len=3"`
+		Note string `validate:"len=10"`
+	}
+	type TestData struct {
+		Num    int    `validate:"gt=10,ls=100"`
+		Str    string `validate:"required"`
+		Name   string `validate:"len=4"`
+		Nested Nested
+	}
+
+	validate := New(WithMergeRules())
+	err := validate.RegisterMapRule(TestData{}, map[string]interface{}{
+		"Num":  "gt=20,eq=50|eq=60",
+		"Name": CLEAR_RULE,
+		"Nested": map[string]interface{}{
+			"Code": "len=2",
+		},
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		Num:    50,
+		Str:    "x",
+		Name:   "anything",
+		Nested: Nested{Code: "ab", Note: "0123456789"},
+	})
+	assert.NoError(t, err)
+
+	err = validate.ValidateStruct(TestData{
+		Num:    15,
+		Nested: Nested{Code: "abc"},
+	})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Num", "TestData.Num", "TestData.Str", "TestData.Nested.Code", "TestData.Nested.Note"},
+		[]string{"gt=20", "eq=50|eq=60", "required", "len=2", "len=10"},
+	))
+
+	t.Run("without merge", func(t *testing.T) {
+		validate := New()
+		err := validate.RegisterMapRule(TestData{}, map[string]interface{}{
+			"Num": "gt=20",
+			"Nested": map[string]interface{}{
+				"Code": "len=2",
+			},
+		})
+		assert.NoError(t, err)
+
+		err = validate.ValidateStruct(TestData{Num: 200, Nested: Nested{Code: "ab"}})
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidationGroup(t *testing.T) {
+	type Profile struct {
+		Bio string `validate:"len=0|regex=^[a-z ]+$" validate_update:"required"`
+	}
+	type User struct {
+		ID      string `validate:"uuid" validate_create:"!required" validate_update:"required,uuid"`
+		Name    string `validate:"required"`
+		Profile Profile
+	}
+
+	validate := New()
+	user := User{Name: "john", Profile: Profile{Bio: "hello"}}
+	assert.NoError(t, validate.ValidateStructGroup(user, "create"))
+
+	err := validate.ValidateStructGroup(user, "update")
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"User.ID", "User.ID"},
+		[]string{"required", "uuid"},
+	))
+
+	err = validate.ValidateStruct(user)
+	assert.EqualError(t, err, combineValidateError([]string{"User.ID"}, []string{"uuid"}))
+
+	user.ID = "8c2a2d1e-2f4b-4b7e-9a55-6d0f3b1e2a7c"
+	user.Profile.Bio = ""
+	err = validate.ValidateStructGroup(user, "create")
+	assert.EqualError(t, err, combineValidateError([]string{"User.ID"}, []string{"!required"}))
+	err = validate.ValidateStructGroup(user, "update")
+	assert.EqualError(t, err, combineValidateError([]string{"User.Profile.Bio"}, []string{"required"}))
+
+	t.Run("map rule of group", func(t *testing.T) {
+		err := validate.RegisterMapRuleGroup(User{}, "admin", map[string]interface{}{
+			"Name": "eq=admin",
+			"Profile": map[string]interface{}{
+				"Bio": "required",
+			},
+		})
+		assert.NoError(t, err)
+
+		// ID falls back to validate tag
+		err = validate.ValidateStructGroup(User{Name: "john"}, "admin")
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"User.ID", "User.Name", "User.Profile.Bio"},
+			[]string{"uuid", "eq=admin", "required"},
+		))
+
+		// fields not in map keep rules of validate_<group> tag
+		err = validate.RegisterMapRuleGroup(User{}, "update", map[string]interface{}{"Name": "len=4"})
+		assert.NoError(t, err)
+		err = validate.ValidateStructGroup(User{Name: "jo", Profile: Profile{Bio: "x"}}, "update")
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"User.ID", "User.ID", "User.Name"},
+			[]string{"required", "uuid", "len=4"},
+		))
+
+		// default rules are not affected
+		err = validate.ValidateStruct(User{ID: "8c2a2d1e-2f4b-4b7e-9a55-6d0f3b1e2a7c", Name: "john"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateStructPartial(t *testing.T) {
+	type Address struct {
+		City   string `validate:"required"`
+		Street string `validate:"required"`
+	}
+	type TestData struct {
+		Name     string  `validate:"required"`
+		Age      int     `validate:"gt=0"`
+		Address  Address `validate:"required"`
+		Shipping Address
+	}
+
+	validate := New()
+	data := TestData{Address: Address{City: "taipei"}}
+
+	t.Run("partial", func(t *testing.T) {
+		err := validate.ValidateStructPartial(data, "Age", "Address.Street")
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"TestData.Age", "TestData.Address.Street"},
+			[]string{"gt=0", "required"},
+		))
+
+		err = validate.ValidateStructPartial(data, "Shipping")
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"TestData.Shipping.City", "TestData.Shipping.Street"},
+			[]string{"required", "required"},
+		))
+
+		assert.NoError(t, validate.ValidateStructPartial(data, "Address.City"))
+	})
+
+	t.Run("except", func(t *testing.T) {
+		err := validate.ValidateStructExcept(data, "Name", "Shipping", "Address.Street")
+		assert.EqualError(t, err, combineValidateError([]string{"TestData.Age"}, []string{"gt=0"}))
+
+		err = validate.ValidateStructExcept(data, "Address.City", "Shipping.City")
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"TestData.Name", "TestData.Age", "TestData.Address.Street", "TestData.Shipping.Street"},
+			[]string{"required", "gt=0", "required", "required"},
+		))
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		err := validate.ValidateStructPartial(data, "Name", "Adress.City")
+		assert.EqualError(t, err, ErrorValidateUnknownKey("Adress.City", "Address.City").Error())
+
+		err = validate.ValidateStructExcept(data, "Address.Cty")
+		assert.EqualError(t, err, ErrorValidateUnknownKey("Address.Cty", "Address.City").Error())
+
+		err = validate.ValidateStructPartial(data, "Age.Value")
+		assert.EqualError(t, err, ErrorValidateUnknownKey("Age.Value", "").Error())
+
+		err = validate.ValidateStructPartial(&data, "Zip")
+		assert.EqualError(t, err, ErrorValidateUnknownKey("Zip", "").Error())
+
+		for _, path := range []string{"", "Address.", ".Name", "Address..City"} {
+			err = validate.ValidateStructPartial(data, path)
+			assert.EqualError(t, err, ErrorValidateEmptyPath(path).Error(), path)
+		}
+	})
+}
+
+func TestValidateJSON(t *testing.T) {
+	type Address struct {
+		City string `json:"city" validate:"required"`
+		Zip  string `json:"zip" validate:"omitempty,len=5"`
+	}
+	type TestData struct {
+		Count   int     `json:"count" validate:"required"`
+		Limit   int     `json:"limit" validate:"omitempty,gt=0"`
+		Name    string  `json:"full/name" validate:"required,len=4"`
+		Address Address `json:"address"`
+	}
+
+	validate := New()
+	t.Run("zero value is present", func(t *testing.T) {
+		var data TestData
+		err := validate.ValidateJSON([]byte(`{"count":0,"full/name":"john","address":{"city":"taipei"}}`), &data)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing and invalid", func(t *testing.T) {
+		var data TestData
+		err := validate.ValidateJSON([]byte(`{"limit":0,"full/name":"jo","address":{"zip":"123"}}`), &data)
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"/count", "/limit", "/full~1name", "/address/city", "/address/zip"},
+			[]string{"required", "gt=0", "len=4", "required", "len=5"},
+		))
+	})
+
+	t.Run("null is absent", func(t *testing.T) {
+		var data TestData
+		err := validate.ValidateJSON([]byte(`{"count":null,"full/name":"john","address":null}`), &data)
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"/count", "/address/city"},
+			[]string{"required", "required"},
+		))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		var data TestData
+		assert.EqualError(t, validate.ValidateJSON([]byte(`{}`), data), ErrorValidateWrongType("pointer to struct").Error())
+
+		var syntaxErr *json.SyntaxError
+		assert.ErrorAs(t, validate.ValidateJSON([]byte(`{"count":`), &data), &syntaxErr)
+	})
+}
+
+func TestOmitEmpty(t *testing.T) {
+	type TestData struct {
+		Email string `validate:"omitempty,email"`
+		Num   *int   `validate:"omitempty,gt=3"`
+	}
+
+	validate := New()
+	assert.NoError(t, validate.ValidateStruct(TestData{}))
+	err := validate.ValidateStruct(TestData{Email: "x", Num: toPtr(0)})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Email", "TestData.Num"},
+		[]string{"email", "gt=3"},
+	))
+	assert.NoError(t, validate.ValidateVar("", "omitempty,email"))
+
+	t.Run("alias", func(t *testing.T) {
+		type TestData struct {
+			Email string `validate:"optemail"`
+			N     int    `json:"n" validate:"optreq"`
+		}
+		validate := New()
+		assert.NoError(t, validate.RegisterAlias("optemail", "omitempty,email"))
+		assert.NoError(t, validate.RegisterAlias("optreq", "required"))
+
+		assert.NoError(t, validate.ValidateVar("", "optemail"))
+		assert.NoError(t, validate.ValidateStruct(TestData{N: 1}))
+		err := validate.ValidateStruct(TestData{Email: "x", N: 1})
+		assert.EqualError(t, err, ValidateErrors{{Field: "TestData.Email", Rule: "optemail", Expanded: "email"}}.Error())
+
+		var data TestData
+		assert.NoError(t, validate.ValidateJSON([]byte(`{"n":0}`), &data))
+		err = validate.ValidateJSON([]byte(`{}`), &data)
+		assert.EqualError(t, err, ValidateErrors{{Field: "/n", Rule: "optreq", Expanded: "required"}}.Error())
+	})
+}
+
+func TestBind(t *testing.T) {
+	type Address struct {
+		City string `query:"city" validate:"required"`
+		Zip  string `query:"zip" validate:"omitempty,len=5"`
+	}
+	type TestData struct {
+		Page    int      `query:"page" validate:"required,gt=0"`
+		Limit   *uint8   `query:"limit" validate:"omitempty,lte=50"`
+		Tags    []string `query:"tag" validate:"len=2"`
+		IDs     []int    `query:"id"`
+		Debug   bool     `query:"debug"`
+		Address Address  `query:"address"`
+		Backup  *Address `query:"backup"`
+		Skip    int      `query:"-" validate:"gt=0"`
+	}
+
+	validate := New()
+	t.Run("bind values", func(t *testing.T) {
+		var data TestData
+		values, _ := url.ParseQuery("page=2&limit=10&tag=a&tag=b&id=1&id=2&debug=true&address.city=taipei&backup.city=tainan")
+		err := validate.BindQuery(&data, values)
+		assert.EqualError(t, err, combineValidateError([]string{"Skip"}, []string{"gt=0"}))
+		assert.Equal(t, 2, data.Page)
+		assert.Equal(t, uint8(10), *data.Limit)
+		assert.Equal(t, []string{"a", "b"}, data.Tags)
+		assert.Equal(t, []int{1, 2}, data.IDs)
+		assert.True(t, data.Debug)
+		assert.Equal(t, "taipei", data.Address.City)
+		assert.Equal(t, &Address{City: "tainan"}, data.Backup)
+	})
+
+	t.Run("conversion failure and missing key", func(t *testing.T) {
+		data := TestData{Skip: 1}
+		values, _ := url.ParseQuery("page=x&limit=300&id=1&id=a&tag=a&address.zip=123")
+		err := validate.BindQuery(&data, values)
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"page", "limit", "id", "tag", "address.city", "address.zip"},
+			[]string{"type=int", "type=*uint8", "type=[]int", "len=2", "required", "len=5"},
+		))
+		assert.Nil(t, data.Backup)
+	})
+
+	t.Run("form tag", func(t *testing.T) {
+		type Login struct {
+			User     string `form:"user" validate:"required"`
+			Password string `validate:"required"`
+		}
+		var data Login
+		err := validate.BindForm(&data, url.Values{"user": {""}})
+		assert.EqualError(t, err, combineValidateError([]string{"Password"}, []string{"required"}))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		assert.EqualError(t, validate.BindQuery(TestData{}, nil), ErrorValidateWrongType("pointer to struct").Error())
+	})
+}
+
+func TestApplyDefaults(t *testing.T) {
+	type Page struct {
+		Size int    `default:"20" validate:"lte=50"`
+		Sort string `default:"asc" validate:"oneof=asc desc"`
+	}
+	type TestData struct {
+		Page    Page
+		Prev    *Page
+		Next    *Page
+		Tags    []string      `default:"a,b"`
+		Timeout time.Duration `default:"1m"`
+		Keep    int           `default:"3"`
+		secret  string        `default:"s3cr3t"`
+	}
+
+	validate := New()
+	data := TestData{Next: &Page{Size: 10}, Keep: 5}
+	assert.NoError(t, validate.ValidateAndDefault(&data))
+	assert.Equal(t, TestData{
+		Page:    Page{Size: 20, Sort: "asc"},
+		Next:    &Page{Size: 10, Sort: "asc"},
+		Tags:    []string{"a", "b"},
+		Timeout: time.Minute,
+		Keep:    5,
+		secret:  "s3cr3t",
+	}, data)
+
+	data = TestData{Page: Page{Size: 100}}
+	assert.EqualError(t, validate.ValidateAndDefault(&data), combineValidateError([]string{"TestData.Page.Size"}, []string{"lte=50"}))
+
+	type Invalid struct {
+		Num  int  `default:"x"`
+		Flag bool `default:"yes"`
+	}
+	err := validate.ApplyDefaults(&Invalid{})
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 2)
+	assert.Equal(t, "validator.Invalid", tagErrs[0].Struct)
+	assert.Equal(t, "Num", tagErrs[0].Field)
+
+	assert.EqualError(t, validate.ApplyDefaults(TestData{}), ErrorValidateNotPointer(reflect.TypeOf(TestData{})).Error())
+}
+
+func TestModify(t *testing.T) {
+	type Profile struct {
+		Name string `mod:"squash,trim,title"`
+	}
+	type TestData struct {
+		Email   string   `mod:"trim,lower" validate:"email"`
+		Code    *string  `mod:"upper"`
+		Tags    []string `mod:"trim"`
+		Note    string   `mod:"strip_nonprint,squash"`
+		Profile *Profile
+		secret  string `mod:"trim"`
+	}
+
+	validate := New()
+	data := TestData{
+		Email:   "  John@Example.COM ",
+		Code:    toPtr("ab"),
+		Tags:    []string{" a", "b "},
+		Note:    "a\x00b  \t c",
+		Profile: &Profile{Name: "  jOHN   doe "},
+		secret:  " s ",
+	}
+	assert.NoError(t, validate.ValidateAndModify(&data))
+	assert.Equal(t, TestData{
+		Email:   "john@example.com",
+		Code:    toPtr("AB"),
+		Tags:    []string{"a", "b"},
+		Note:    "ab c",
+		Profile: &Profile{Name: "John Doe"},
+		secret:  "s",
+	}, data)
+
+	data = TestData{Email: " x "}
+	assert.EqualError(t, validate.ValidateAndModify(&data), combineValidateError([]string{"TestData.Email"}, []string{"email"}))
+
+	type Invalid struct {
+		Name string `mod:"trim,reverse"`
+		Num  int    `mod:"trim"`
+	}
+	invalid := Invalid{Name: " a "}
+	err := validate.Modify(&invalid)
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 2)
+	assert.Equal(t, "reverse", tagErrs[0].Segment)
+	assert.Equal(t, " a ", invalid.Name)
+
+	assert.EqualError(t, validate.Modify(data), ErrorValidateNotPointer(reflect.TypeOf(data)).Error())
+}
+
+func TestNestedPointer(t *testing.T) {
+	type Address struct {
+		City string `validate:"required"`
+	}
+	type TestData struct {
+		Home *Address
+		Work **Address
+	}
+
+	validate := New()
+	assert.NoError(t, validate.ValidateStruct(TestData{}))
+	work := &Address{}
+	err := validate.ValidateStruct(TestData{Home: &Address{}, Work: &work})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Home.City", "TestData.Work.City"},
+		[]string{"required", "required"},
+	))
+}
+
+func TestNamedType(t *testing.T) {
+	type Age int
+	type Role string
+	type TestData struct {
+		Age  Age  `validate:"gte=18"`
+		Role Role `validate:"oneof=admin user,len=4"`
+	}
+
+	validate := New()
+	assert.NoError(t, validate.ValidateStruct(TestData{Age: 20, Role: "user"}))
+	err := validate.ValidateStruct(TestData{Age: 3, Role: "admin"})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.Age", "TestData.Role"},
+		[]string{"gte=18", "len=4"},
+	))
+}
+
+func TestRuleKind(t *testing.T) {
+	validate := New()
+	var nilInt *int
+	var nilStr *string
+
+	tests := []struct {
+		value interface{}
+		tag   string
+		pass  bool
+	}{
+		{uint16(7), "oneof=5 6 7", true},
+		{float32(1.5), "oneof=0.5 1.5", true},
+		{complex(1, 2), "oneof=1+2i 3", true},
+		{complex(1, 2), "gte=1+2i", true},
+		{complex(1, 2), "gt=0", false},
+		{math.NaN(), "gte=0", false},
+		{math.NaN(), "not eq=0", true},
+		{[2]int{}, "len=2", true},
+		{nilInt, "gte=0", false},
+		{nilInt, "oneof=0 1", false},
+		{nilInt, "not ls=0", true},
+		{nilStr, "len=0", false},
+		{nilStr, "regex=^$", false},
+		{nilStr, "omitempty,email", true},
+		{toPtr(5), "gt=3", true},
+	}
+	for _, tt := range tests {
+		err := validate.ValidateVar(tt.value, tt.tag)
+		if tt.pass {
+			assert.NoError(t, err, "%T %v", tt.value, tt.tag)
+		} else {
+			assert.Error(t, err, "%T %v", tt.value, tt.tag)
+		}
+	}
+
+	_, err := validate.parseTag(reflect.TypeOf(true), "oneof=true", false)
+	assert.Error(t, err)
+}
+
+func TestFailFast(t *testing.T) {
+	type Address struct {
+		City string `validate:"required"`
+	}
+	type TestData struct {
+		Name    string `validate:"required,len=4"`
+		Address Address
+		Age     int `validate:"gt=0"`
+	}
+
+	validate := New(WithFailFast())
+	err := validate.ValidateStruct(TestData{Age: 1})
+	assert.EqualError(t, err, combineValidateError([]string{"TestData.Name"}, []string{"required"}))
+
+	err = validate.ValidateStruct(TestData{Name: "john"})
+	assert.EqualError(t, err, combineValidateError([]string{"TestData.Address.City"}, []string{"required"}))
+}
+
+func TestValidateSlice(t *testing.T) {
+	type Item struct {
+		ID   int    `validate:"gt=0"`
+		Name string `validate:"required"`
+	}
+	items := make([]*Item, 10000)
+	var fields, rules []string
+	for i := range items {
+		items[i] = &Item{ID: i + 1, Name: "item"}
+		switch {
+		case i%1000 == 7:
+			items[i].ID = 0
+			fields, rules = append(fields, fmt.Sprintf("[%v].ID", i)), append(rules, "gt=0")
+		case i%1000 == 500:
+			items[i] = nil
+			fields, rules = append(fields, fmt.Sprintf("[%v]", i)), append(rules, "required")
+		}
+	}
+	ctx := context.Background()
+
+	for _, workers := range []int{0, 1, 4, 64} {
+		validate := New(WithWorkers(workers))
+		err := validate.ValidateSlice(ctx, items)
+		assert.EqualError(t, err, combineValidateError(fields, rules), "workers %v", workers)
+		assert.NoError(t, validate.ValidateSlice(ctx, items[:7]))
+		assert.NoError(t, validate.ValidateSlice(ctx, []Item{}))
+
+		validate = New(WithWorkers(workers), WithFailFast())
+		err = validate.ValidateSlice(ctx, items)
+		assert.EqualError(t, err, combineValidateError(fields[:1], rules[:1]), "workers %v", workers)
+	}
+
+	t.Run("array", func(t *testing.T) {
+		err := New().ValidateSlice(ctx, [2]Item{{ID: 1, Name: "a"}, {ID: 2}})
+		assert.EqualError(t, err, combineValidateError([]string{"[1].Name"}, []string{"required"}))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := New(WithWorkers(4)).ValidateSlice(ctx, items)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		assert.EqualError(t, New().ValidateSlice(ctx, Item{}), ErrorValidateWrongType("slice").Error())
+		assert.EqualError(t, New().ValidateSlice(ctx, []int{1}), ErrorValidateWrongType("slice of struct").Error())
+	})
+}
+
+func TestCompile(t *testing.T) {
+	type Address struct {
+		City string `validate:"required"`
+	}
+	type Valid struct {
+		Name    string `validate:"required,len=4|len=6,!eq=root"`
+		Address *Address
+	}
+	type Invalid struct {
+		Age  int    `validate:"gt=abc"`
+		Role string `validate:"unknown"`
+	}
+
+	validate := New()
+	assert.NoError(t, validate.Compile(Valid{}, reflect.TypeOf(Address{})))
+	assert.NotNil(t, validate.loadRule("", reflect.TypeOf(Valid{}).String()))
+
+	err := validate.Compile((*Invalid)(nil), 3, Valid{})
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Len(t, tagErrs, 3)
+	assert.Equal(t, "Age", tagErrs[0].Field)
+	assert.Equal(t, "Role", tagErrs[1].Field)
+	assert.Equal(t, "int", tagErrs[2].Struct)
+
+	assert.NoError(t, validate.RegisterAlias("shortname", "len=4"))
+	rules, err := validate.Rules(&Valid{})
+	assert.NoError(t, err)
+	assert.Equal(t, "validator.Valid", rules.Name)
+	assert.Equal(t, []Rule{
+		{Tag: "required", Name: "required"},
+		{Tag: "len=4|len=6", Any: []Rule{
+			{Tag: "len=4", Name: "len", Param: "4"},
+			{Tag: "len=6", Name: "len", Param: "6"},
+		}},
+		{Tag: "!eq=root", Name: "eq", Param: "root", Negate: true},
+	}, rules.Fields[0].Rules)
+	assert.Nil(t, rules.Fields[1].Rules)
+	assert.Equal(t, []Rule{{Tag: "required", Name: "required"}}, rules.Fields[1].Nested.Fields[0].Rules)
+
+	type Aliased struct {
+		Name string `validate:"shortname"`
+	}
+	rules, err = validate.Rules(Aliased{})
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{{Tag: "shortname", Name: "shortname", Expanded: "len=4"}}, rules.Fields[0].Rules)
+
+	_, err = validate.Rules(Invalid{})
+	assert.ErrorAs(t, err, &tagErrs)
+}
+
+func TestSkipUnexported(t *testing.T) {
+	type inner struct {
+		Code string `validate:"len=3"`
+	}
+	type base struct {
+		ID int `validate:"gt=0"`
+	}
+	type TestData struct {
+		base
+		Name  string `validate:"required"`
+		age   int    `validate:"gt=abc"`
+		inner inner
+	}
+
+	data := TestData{inner: inner{Code: "x"}}
+	err := New().ValidateStruct(data)
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+
+	err = New(WithSkipUnexported()).ValidateStruct(data)
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"TestData.ID", "TestData.Name"},
+		[]string{"gt=0", "required"},
+	))
+
+	t.Run("unaddressable nested value", func(t *testing.T) {
+		type TestData struct {
+			inner inner
+		}
+		err := New().ValidateStruct(TestData{inner: inner{Code: "x"}})
+		assert.EqualError(t, err, combineValidateError([]string{"TestData.inner.Code"}, []string{"len=3"}))
+	})
+}
+
+func TestEmbedded(t *testing.T) {
+	type BaseModel struct {
+		ID   int    `json:"id" query:"id" validate:"gt=0"`
+		Name string `json:"name" query:"name"`
+	}
+	type User struct {
+		BaseModel
+		Name  string `json:"name" query:"name" validate:"required"`
+		Email string `json:"email" query:"email"`
+	}
+
+	err := New().ValidateStruct(User{})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"User.ID", "User.Name"},
+		[]string{"gt=0", "required"},
+	))
+	err = New(WithNamespacedEmbedded()).ValidateStruct(User{})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"User.BaseModel.ID", "User.Name"},
+		[]string{"gt=0", "required"},
+	))
+
+	t.Run("map rule", func(t *testing.T) {
+		type Base struct {
+			ID    int
+			Name  string
+			Email string
+		}
+		type Account struct {
+			Base
+			Email string
+		}
+
+		validate := New()
+		err := validate.RegisterMapRule(Account{}, map[string]interface{}{
+			"ID":    "gt=0",
+			"Name":  "required",
+			"Email": "required",
+		})
+		assert.NoError(t, err)
+		err = validate.ValidateStruct(Account{})
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"Account.ID", "Account.Name", "Account.Email"},
+			[]string{"gt=0", "required", "required"},
+		))
+
+		// rules of promoted fields don't apply to the embedded type elsewhere
+		type Other struct {
+			Base
+		}
+		assert.NoError(t, validate.ValidateStruct(Base{}))
+		assert.NoError(t, validate.ValidateStruct(Other{}))
+		err = validate.ValidateStruct(Account{})
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"Account.ID", "Account.Name", "Account.Email"},
+			[]string{"gt=0", "required", "required"},
+		))
+
+		err = validate.RegisterMapRule(Account{}, map[string]interface{}{
+			"Base": map[string]interface{}{"ID": "gt=1"},
+		})
+		assert.NoError(t, err)
+		err = validate.RegisterMapRule(Account{}, map[string]interface{}{"Id": "gt=0"})
+		var tagErrs TagErrors
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnknownKey("Id", "ID").Error())
+
+		validate = New(WithNamespacedEmbedded())
+		err = validate.RegisterMapRule(Account{}, map[string]interface{}{"ID": "gt=0"})
+		assert.ErrorAs(t, err, &tagErrs)
+		assert.EqualError(t, tagErrs[0].Err, ErrorValidateUnknownKey("ID", "").Error())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var u User
+		err := New().ValidateJSON([]byte(`{"id":0,"name":"john"}`), &u)
+		assert.EqualError(t, err, combineValidateError([]string{"/id"}, []string{"gt=0"}))
+		assert.Equal(t, "john", u.Name)
+	})
+
+	t.Run("json unexported embedded", func(t *testing.T) {
+		type base struct {
+			ID int `json:"id" validate:"required"`
+		}
+		type Item struct {
+			base
+			Name string `json:"name" validate:"required"`
+		}
+		var item Item
+		assert.NoError(t, New().ValidateJSON([]byte(`{"id":0,"name":"a"}`), &item))
+		err := New().ValidateJSON([]byte(`{"name":"a"}`), &item)
+		assert.EqualError(t, err, combineValidateError([]string{"/id"}, []string{"required"}))
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		var u User
+		err := New().BindQuery(&u, url.Values{"id": {"x"}, "name": {"john"}})
+		assert.EqualError(t, err, combineValidateError([]string{"id"}, []string{"type=int"}))
+
+		err = New().BindQuery(&u, url.Values{"id": {"3"}, "name": {"john"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, u.ID)
+	})
+
+	t.Run("bind unexported embedded", func(t *testing.T) {
+		type base struct {
+			ID     int `query:"id" validate:"gt=0"`
+			secret string
+		}
+		type Item struct {
+			base
+			Name string `query:"name" validate:"required"`
+		}
+		var item Item
+		err := New().BindQuery(&item, url.Values{"id": {"3"}, "name": {"a"}, "secret": {"x"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, item.ID)
+		assert.Empty(t, item.secret)
+
+		err = New().BindQuery(&item, url.Values{"id": {"0"}, "name": {"a"}})
+		assert.EqualError(t, err, combineValidateError([]string{"id"}, []string{"gt=0"}))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		err := New().ValidateStructPartial(User{}, "ID")
+		assert.EqualError(t, err, combineValidateError([]string{"User.ID"}, []string{"gt=0"}))
+		err = New().ValidateStructExcept(User{}, "ID")
+		assert.EqualError(t, err, combineValidateError([]string{"User.Name"}, []string{"required"}))
+		err = New().ValidateStructPartial(User{}, "BaseModel.ID")
+		assert.EqualError(t, err, ErrorValidateUnknownKey("BaseModel.ID", "").Error())
+
+		err = New(WithNamespacedEmbedded()).ValidateStructPartial(User{}, "BaseModel.ID")
+		assert.EqualError(t, err, combineValidateError([]string{"User.BaseModel.ID"}, []string{"gt=0"}))
+	})
+}
+
+type testPayload interface {
+	Kind() string
+}
+
+type testOrder struct {
+	ID    int `validate:"gt=0"`
+	Items []string
+}
+
+func (testOrder) Kind() string { return "order" }
+
+func TestInterfaceField(t *testing.T) {
+	type Invalid struct {
+		Age int `validate:"gt=abc"`
+	}
+	type Event struct {
+		Name    string      `validate:"required"`
+		Payload testPayload `validate:"required"`
+		Extra   interface{}
+	}
+
+	validate := New()
+	err := validate.ValidateStruct(Event{Name: "created", Payload: testOrder{}})
+	assert.EqualError(t, err, combineValidateError([]string{"Event.Payload.ID"}, []string{"gt=0"}))
+
+	err = validate.ValidateStruct(Event{Name: "created", Payload: &testOrder{ID: 1}, Extra: &Event{Payload: testOrder{}}})
+	assert.EqualError(t, err, combineValidateError(
+		[]string{"Event.Extra.Name", "Event.Extra.Payload.ID"},
+		[]string{"required", "gt=0"},
+	))
+
+	err = validate.ValidateStruct(Event{Payload: (*testOrder)(nil), Extra: 3})
+	assert.EqualError(t, err, combineValidateError([]string{"Event.Name"}, []string{"required"}))
+
+	err = validate.ValidateStruct(Event{})
+	assert.EqualError(t, err, combineValidateError([]string{"Event.Name", "Event.Payload"}, []string{"required", "required"}))
+
+	err = validate.ValidateStruct(Event{Name: "created", Payload: testOrder{ID: 1}, Extra: Invalid{}})
+	var tagErrs TagErrors
+	assert.ErrorAs(t, err, &tagErrs)
+	assert.Equal(t, "Age", tagErrs[0].Field)
+
+	t.Run("json pointer", func(t *testing.T) {
+		e := Event{Payload: &testOrder{}}
+		err := validate.ValidateJSON([]byte(`{"Name":"created","Payload":{"ID":0}}`), &e)
+		assert.EqualError(t, err, combineValidateError([]string{"/Payload/ID"}, []string{"gt=0"}))
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		events := []Event{{Name: "a", Payload: testOrder{ID: 1}}, {Name: "b", Payload: testOrder{}}}
+		err := New(WithWorkers(2)).ValidateSlice(context.Background(), events)
+		assert.EqualError(t, err, combineValidateError([]string{"[1].Payload.ID"}, []string{"gt=0"}))
+
+		events[0].Extra = Invalid{}
+		err = New(WithWorkers(2)).ValidateSlice(context.Background(), events)
+		assert.ErrorAs(t, err, &tagErrs)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		type pNode struct {
+			Name string `validate:"required"`
+			Next interface{}
+			Prev interface{}
+		}
+		n := &pNode{}
+		n.Next = n
+		err := validate.ValidateStruct(n)
+		assert.EqualError(t, err, combineValidateError([]string{"pNode.Name"}, []string{"required"}))
+
+		a, b := &pNode{Name: "a"}, &pNode{}
+		a.Next, b.Next = b, a
+		err = validate.ValidateStruct(a)
+		assert.EqualError(t, err, combineValidateError([]string{"pNode.Next.Name"}, []string{"required"}))
+
+		// struct reached by multiple paths is not a cycle
+		a.Next, a.Prev, b.Next = b, b, nil
+		err = validate.ValidateStruct(a)
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"pNode.Next.Name", "pNode.Prev.Name"},
+			[]string{"required", "required"},
+		))
+
+		b.Next = a
+		err = New(WithWorkers(2)).ValidateSlice(context.Background(), []*pNode{a, b})
+		assert.EqualError(t, err, combineValidateError(
+			[]string{"[0].Next.Name", "[0].Prev.Name", "[1].Name"},
+			[]string{"required", "required", "required"},
+		))
 	})
 }
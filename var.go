@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// varKey identify parsed tag of a standalone variable
+type varKey struct {
+	vType reflect.Type
+	tag   string
+}
+
+// ValidateVar validate a single variable against tag, e.g.
+// ValidateVar(ids, "required,len=3"). failed rules are reported with the
+// type of value as field name.
+func (v *Validator) ValidateVar(value interface{}, tag string) error {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() {
+		return ErrorValidateWrongType("variable")
+	}
+
+	fs, err := v.loadVarRule(rv.Type(), tag)
+	if err != nil {
+		return withField(err, rv.Type().String(), "")
+	}
+	if errors := checkVar(rv, fs, rv.Type().String()); len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// loadVarRule return parsed tag for variable of vType, parsing it if not cached
+func (v *Validator) loadVarRule(vType reflect.Type, tag string) ([]*validateFn, error) {
+	key := varKey{vType, tag}
+	if cached, ok := v.varCache.Load(key); ok {
+		return cached.([]*validateFn), nil
+	}
+
+	fieldType, isPtr := derefType(vType)
+	fs, err := v.parseTag(fieldType, tag, isPtr)
+	if err != nil {
+		return nil, err
+	}
+	v.varCache.Store(key, fs)
+	return fs, nil
+}
+
+// ValidateVarWithValue validate a against tag, where rules written without
+// param are compared with b, e.g. ValidateVarWithValue(5, 3, "gt") checks 5 > 3.
+func (v *Validator) ValidateVarWithValue(a, b interface{}, tag string) error {
+	rv := reflect.ValueOf(a)
+	if !rv.IsValid() {
+		return ErrorValidateWrongType("variable")
+	}
+
+	segments, err := parseTagExpr(tag)
+	if err != nil {
+		return withField(err, rv.Type().String(), "")
+	}
+	fillParam(segments, fmt.Sprint(b))
+
+	vType, isPtr := derefType(rv.Type())
+	fs, err := v.buildTag(vType, tag, segments, isPtr)
+	if err != nil {
+		return withField(err, rv.Type().String(), "")
+	}
+	if errors := checkVar(rv, fs, rv.Type().String()); len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// rules which cannot be used without param
+var paramRules = map[string]bool{
+	"gt": true, "eq": true, "ls": true, "gte": true, "lte": true,
+	"len": true, "min": true, "max": true, "oneof": true, "regex": true,
+}
+
+// fillParam set param of rules written without param
+func fillParam(nodes []*tagNode, param string) {
+	for _, n := range nodes {
+		if n.Kind != nodeRule {
+			fillParam(n.Children, param)
+			continue
+		}
+		if !n.HasParam && paramRules[n.Name] {
+			n.Param, n.HasParam = param, true
+		}
+	}
+}
+
+// checkVar run fs on value and report failed rules with name
+func checkVar(value reflect.Value, fs []*validateFn, name string) ValidateErrors {
+	if hasOmitEmpty(fs) && value.IsZero() {
+		return nil
+	}
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	var errors ValidateErrors
+	for _, vf := range fs {
+		if !vf.CheckPass(value) {
+			errors = append(errors, newValidateError(name, vf))
+		}
+	}
+	return errors
+}